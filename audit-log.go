@@ -0,0 +1,109 @@
+package tempest
+
+import "encoding/json"
+
+// https://docs.discord.com/developers/resources/audit-log#audit-log-entry-object-audit-log-events
+type AuditLogEvent uint8
+
+const (
+	GUILD_UPDATE_AUDIT_LOG_EVENT                                AuditLogEvent = 1
+	CHANNEL_CREATE_AUDIT_LOG_EVENT                              AuditLogEvent = 10
+	CHANNEL_UPDATE_AUDIT_LOG_EVENT                              AuditLogEvent = 11
+	CHANNEL_DELETE_AUDIT_LOG_EVENT                              AuditLogEvent = 12
+	CHANNEL_OVERWRITE_CREATE_AUDIT_LOG_EVENT                    AuditLogEvent = 13
+	CHANNEL_OVERWRITE_UPDATE_AUDIT_LOG_EVENT                    AuditLogEvent = 14
+	CHANNEL_OVERWRITE_DELETE_AUDIT_LOG_EVENT                    AuditLogEvent = 15
+	MEMBER_KICK_AUDIT_LOG_EVENT                                 AuditLogEvent = 20
+	MEMBER_PRUNE_AUDIT_LOG_EVENT                                AuditLogEvent = 21
+	MEMBER_BAN_ADD_AUDIT_LOG_EVENT                              AuditLogEvent = 22
+	MEMBER_BAN_REMOVE_AUDIT_LOG_EVENT                           AuditLogEvent = 23
+	MEMBER_UPDATE_AUDIT_LOG_EVENT                               AuditLogEvent = 24
+	MEMBER_ROLE_UPDATE_AUDIT_LOG_EVENT                          AuditLogEvent = 25
+	MEMBER_MOVE_AUDIT_LOG_EVENT                                 AuditLogEvent = 26
+	MEMBER_DISCONNECT_AUDIT_LOG_EVENT                           AuditLogEvent = 27
+	BOT_ADD_AUDIT_LOG_EVENT                                     AuditLogEvent = 28
+	ROLE_CREATE_AUDIT_LOG_EVENT                                 AuditLogEvent = 30
+	ROLE_UPDATE_AUDIT_LOG_EVENT                                 AuditLogEvent = 31
+	ROLE_DELETE_AUDIT_LOG_EVENT                                 AuditLogEvent = 32
+	INVITE_CREATE_AUDIT_LOG_EVENT                               AuditLogEvent = 40
+	INVITE_UPDATE_AUDIT_LOG_EVENT                               AuditLogEvent = 41
+	INVITE_DELETE_AUDIT_LOG_EVENT                               AuditLogEvent = 42
+	WEBHOOK_CREATE_AUDIT_LOG_EVENT                              AuditLogEvent = 50
+	WEBHOOK_UPDATE_AUDIT_LOG_EVENT                              AuditLogEvent = 51
+	WEBHOOK_DELETE_AUDIT_LOG_EVENT                              AuditLogEvent = 52
+	EMOJI_CREATE_AUDIT_LOG_EVENT                                AuditLogEvent = 60
+	EMOJI_UPDATE_AUDIT_LOG_EVENT                                AuditLogEvent = 61
+	EMOJI_DELETE_AUDIT_LOG_EVENT                                AuditLogEvent = 62
+	MESSAGE_DELETE_AUDIT_LOG_EVENT                              AuditLogEvent = 72
+	MESSAGE_BULK_DELETE_AUDIT_LOG_EVENT                         AuditLogEvent = 73
+	MESSAGE_PIN_AUDIT_LOG_EVENT                                 AuditLogEvent = 74
+	MESSAGE_UNPIN_AUDIT_LOG_EVENT                               AuditLogEvent = 75
+	INTEGRATION_CREATE_AUDIT_LOG_EVENT                          AuditLogEvent = 80
+	INTEGRATION_UPDATE_AUDIT_LOG_EVENT                          AuditLogEvent = 81
+	INTEGRATION_DELETE_AUDIT_LOG_EVENT                          AuditLogEvent = 82
+	STAGE_INSTANCE_CREATE_AUDIT_LOG_EVENT                       AuditLogEvent = 83
+	STAGE_INSTANCE_UPDATE_AUDIT_LOG_EVENT                       AuditLogEvent = 84
+	STAGE_INSTANCE_DELETE_AUDIT_LOG_EVENT                       AuditLogEvent = 85
+	STICKER_CREATE_AUDIT_LOG_EVENT                              AuditLogEvent = 90
+	STICKER_UPDATE_AUDIT_LOG_EVENT                              AuditLogEvent = 91
+	STICKER_DELETE_AUDIT_LOG_EVENT                              AuditLogEvent = 92
+	GUILD_SCHEDULED_EVENT_CREATE_AUDIT_LOG_EVENT                AuditLogEvent = 100
+	GUILD_SCHEDULED_EVENT_UPDATE_AUDIT_LOG_EVENT                AuditLogEvent = 101
+	GUILD_SCHEDULED_EVENT_DELETE_AUDIT_LOG_EVENT                AuditLogEvent = 102
+	THREAD_CREATE_AUDIT_LOG_EVENT                               AuditLogEvent = 110
+	THREAD_UPDATE_AUDIT_LOG_EVENT                               AuditLogEvent = 111
+	THREAD_DELETE_AUDIT_LOG_EVENT                               AuditLogEvent = 112
+	APPLICATION_COMMAND_PERMISSION_UPDATE_AUDIT_LOG_EVENT       AuditLogEvent = 121
+	SOUNDBOARD_SOUND_CREATE_AUDIT_LOG_EVENT                     AuditLogEvent = 130
+	SOUNDBOARD_SOUND_UPDATE_AUDIT_LOG_EVENT                     AuditLogEvent = 131
+	SOUNDBOARD_SOUND_DELETE_AUDIT_LOG_EVENT                     AuditLogEvent = 132
+	AUTO_MODERATION_RULE_CREATE_AUDIT_LOG_EVENT                 AuditLogEvent = 140
+	AUTO_MODERATION_RULE_UPDATE_AUDIT_LOG_EVENT                 AuditLogEvent = 141
+	AUTO_MODERATION_RULE_DELETE_AUDIT_LOG_EVENT                 AuditLogEvent = 142
+	AUTO_MODERATION_BLOCK_MESSAGE_AUDIT_LOG_EVENT               AuditLogEvent = 143
+	AUTO_MODERATION_FLAG_TO_CHANNEL_AUDIT_LOG_EVENT             AuditLogEvent = 144
+	AUTO_MODERATION_USER_COMMUNICATION_DISABLED_AUDIT_LOG_EVENT AuditLogEvent = 145
+	CREATOR_MONETIZATION_REQUEST_CREATED_AUDIT_LOG_EVENT        AuditLogEvent = 150
+	CREATOR_MONETIZATION_TERMS_ACCEPTED_AUDIT_LOG_EVENT         AuditLogEvent = 151
+	ONBOARDING_PROMPT_CREATE_AUDIT_LOG_EVENT                    AuditLogEvent = 163
+	ONBOARDING_PROMPT_UPDATE_AUDIT_LOG_EVENT                    AuditLogEvent = 164
+	ONBOARDING_PROMPT_DELETE_AUDIT_LOG_EVENT                    AuditLogEvent = 165
+	ONBOARDING_CREATE_AUDIT_LOG_EVENT                           AuditLogEvent = 166
+	ONBOARDING_UPDATE_AUDIT_LOG_EVENT                           AuditLogEvent = 167
+	HOME_SETTINGS_CREATE_AUDIT_LOG_EVENT                        AuditLogEvent = 190
+	HOME_SETTINGS_UPDATE_AUDIT_LOG_EVENT                        AuditLogEvent = 191
+)
+
+// https://docs.discord.com/developers/resources/audit-log#audit-log-entry-object-audit-log-entry-structure
+type AuditLogEntry struct {
+	Options    *AuditLogEntryInfo `json:"options,omitempty"` // Additional info for certain event types.
+	Reason     string             `json:"reason,omitempty"`  // Reason for the change (1-512 characters).
+	Changes    []AuditLogChange   `json:"changes,omitzero"`
+	TargetID   Snowflake          `json:"target_id,omitempty"` // ID of the affected entity (webhook, user, role, etc.).
+	UserID     Snowflake          `json:"user_id,omitempty"`   // User or app that made the changes.
+	ID         Snowflake          `json:"id"`
+	ActionType AuditLogEvent      `json:"action_type"`
+}
+
+// https://docs.discord.com/developers/resources/audit-log#audit-log-change-object
+type AuditLogChange struct {
+	NewValue json.RawMessage `json:"new_value,omitempty"` // Type depends on the key - see Discord docs for exceptions.
+	OldValue json.RawMessage `json:"old_value,omitempty"`
+	Key      string          `json:"key"`
+}
+
+// https://docs.discord.com/developers/resources/audit-log#audit-log-entry-object-optional-audit-entry-info
+type AuditLogEntryInfo struct {
+	AutoModerationRuleName        string    `json:"auto_moderation_rule_name,omitempty"`
+	AutoModerationRuleTriggerType string    `json:"auto_moderation_rule_trigger_type,omitempty"`
+	Count                         string    `json:"count,omitempty"`              // Number of entities that were targeted.
+	DeleteMemberDays              string    `json:"delete_member_days,omitempty"` // Number of days after which inactive members were kicked.
+	MembersRemoved                string    `json:"members_removed,omitempty"`
+	RoleName                      string    `json:"role_name,omitempty"`
+	Type                          string    `json:"type,omitempty"`             // Type of overwritten entity - role ("0") or member ("1").
+	IntegrationType               string    `json:"integration_type,omitempty"` // The type of integration which performed the action.
+	ApplicationID                 Snowflake `json:"application_id,omitempty"`
+	ChannelID                     Snowflake `json:"channel_id,omitempty"`
+	ID                            Snowflake `json:"id,omitempty"` // ID of the overwritten entity.
+	MessageID                     Snowflake `json:"message_id,omitempty"`
+}
@@ -0,0 +1,262 @@
+package tempest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MAX_AUTO_COMPLETE_CHOICES = 25              // Discord rejects auto complete responses with more choices.
+	AUTO_COMPLETE_DEADLINE    = 3 * time.Second // Time app has to respond to auto complete interaction.
+)
+
+// Folds common Latin letters with diacritics into their base form, so "zolw" matches "żółw".
+var diacriticsReplacer = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a", "ă", "a", "ą", "a",
+	"ç", "c", "ć", "c", "č", "c", "ď", "d", "đ", "d",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e", "ė", "e", "ę", "e", "ě", "e",
+	"ğ", "g", "ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i", "į", "i", "ı", "i",
+	"ľ", "l", "ĺ", "l", "ł", "l", "ñ", "n", "ń", "n", "ň", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "ō", "o", "ő", "o",
+	"ŕ", "r", "ř", "r", "ś", "s", "š", "s", "ş", "s", "ș", "s", "ß", "ss",
+	"ť", "t", "ţ", "t", "ț", "t", "ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u", "ų", "u",
+	"ý", "y", "ÿ", "y", "ź", "z", "ż", "z", "ž", "z", "æ", "ae", "œ", "oe",
+)
+
+// Lower cases text (with locale specific rules) and strips diacritics, so it can be compared loosely.
+func foldForSearch(text string, locale Language) string {
+	switch locale {
+	case TURKISH_LANGUAGE:
+		text = strings.ToLowerSpecial(unicode.TurkishCase, text)
+	default:
+		text = strings.ToLower(text)
+	}
+	return diacriticsReplacer.Replace(strings.TrimSpace(text))
+}
+
+type rankedChoice struct {
+	choice CommandOptionChoice
+	tier   int // 0 = exact, 1 = prefix, 2 = word prefix, 3 = substring, 4 = fuzzy.
+	score  int // Secondary order within tier (position of match or edit distance).
+	length int
+	index  int
+}
+
+// Ranks candidates against query typed by user and returns up to 25 best matches, ready to be returned from auto complete handler.
+// Matches are ordered by quality: exact match, prefix, prefix of any word, substring and finally close matches with typos.
+// Comparison ignores letter case & diacritics and uses localized names (for provided locale) when candidates have them.
+// Empty query returns first 25 candidates in original order.
+func RankChoices(query string, candidates []CommandOptionChoice, locale Language) []CommandOptionChoice {
+	query = foldForSearch(query, locale)
+	if query == "" {
+		return slices.Clone(candidates[:min(len(candidates), MAX_AUTO_COMPLETE_CHOICES)])
+	}
+
+	queryLength := utf8.RuneCountInString(query)
+	maxDistance := queryLength / 3
+
+	ranked := make([]rankedChoice, 0, len(candidates))
+	for i, candidate := range candidates {
+		name := candidate.Name
+		if localized, ok := candidate.NameLocalizations[locale]; ok && localized != "" {
+			name = localized
+		}
+
+		folded := foldForSearch(name, locale)
+		entry := rankedChoice{choice: candidate, length: len(folded), index: i}
+
+		switch idx := strings.Index(folded, query); {
+		case folded == query:
+			entry.tier = 0
+		case idx == 0:
+			entry.tier = 1
+		case idx > 0 && isWordStart(folded, query):
+			entry.tier, entry.score = 2, idx
+		case idx > 0:
+			entry.tier, entry.score = 3, idx
+		default:
+			if maxDistance == 0 {
+				continue
+			}
+
+			// Compare against beginning of candidate too, so typos in partially typed words still match.
+			distance := editDistance(query, folded)
+			if prefix := truncateRunes(folded, queryLength); prefix != folded {
+				distance = min(distance, editDistance(query, prefix))
+			}
+
+			if distance > maxDistance {
+				continue
+			}
+			entry.tier, entry.score = 4, distance
+		}
+
+		ranked = append(ranked, entry)
+	}
+
+	slices.SortFunc(ranked, func(a, b rankedChoice) int {
+		return cmp.Or(cmp.Compare(a.tier, b.tier), cmp.Compare(a.score, b.score), cmp.Compare(a.length, b.length), cmp.Compare(a.index, b.index))
+	})
+
+	choices := make([]CommandOptionChoice, 0, min(len(ranked), MAX_AUTO_COMPLETE_CHOICES))
+	for _, entry := range ranked[:min(len(ranked), MAX_AUTO_COMPLETE_CHOICES)] {
+		choices = append(choices, entry.choice)
+	}
+	return choices
+}
+
+// Works like RankChoices, but for plain strings (each string is used as both choice name and value).
+func RankStrings(query string, candidates []string, locale Language) []CommandOptionChoice {
+	choices := make([]CommandOptionChoice, len(candidates))
+	for i, candidate := range candidates {
+		choices[i] = CommandOptionChoice{Name: candidate, Value: candidate}
+	}
+	return RankChoices(query, choices, locale)
+}
+
+func isWordStart(text string, query string) bool {
+	for idx := strings.Index(text, query); idx != -1; {
+		previous, _ := utf8.DecodeLastRuneInString(text[:idx])
+		if !unicode.IsLetter(previous) && !unicode.IsDigit(previous) {
+			return true
+		}
+
+		next := strings.Index(text[idx+1:], query)
+		if next == -1 {
+			return false
+		}
+		idx += next + 1
+	}
+	return false
+}
+
+func truncateRunes(text string, n int) string {
+	for i := range text {
+		if n == 0 {
+			return text[:i]
+		}
+		n--
+	}
+	return text
+}
+
+// Levenshtein distance between two strings (counted in runes).
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+type autoCompleteCacheEntry struct {
+	expire  time.Time
+	choices []CommandOptionChoice
+}
+
+// Remembers auto complete results per user, so identical queries (same command, options and typed text) aren't computed again.
+// Discord sends new auto complete interaction for each key stroke - it helps a lot when user deletes & retypes characters
+// or when computing choices is expensive (like fetching them from database or external API).
+type AutoCompleteCache struct {
+	entries   map[string]autoCompleteCacheEntry
+	lastSweep time.Time
+	ttl       time.Duration
+	mu        sync.Mutex
+}
+
+// Creates cache that keeps results for ttl duration.
+func NewAutoCompleteCache(ttl time.Duration) *AutoCompleteCache {
+	if ttl <= 0 {
+		panic("auto complete cache ttl must be greater than 0")
+	}
+
+	return &AutoCompleteCache{
+		entries:   make(map[string]autoCompleteCacheEntry),
+		lastSweep: time.Now(),
+		ttl:       ttl,
+	}
+}
+
+// Wraps auto complete handler with cache. Errors are never cached.
+func (cache *AutoCompleteCache) Wrap(handler func(ctx context.Context, itx CommandInteraction) ([]CommandOptionChoice, error)) func(ctx context.Context, itx CommandInteraction) ([]CommandOptionChoice, error) {
+	return func(ctx context.Context, itx CommandInteraction) ([]CommandOptionChoice, error) {
+		key := autoCompleteCacheKey(itx)
+		if choices, ok := cache.get(key); ok {
+			return choices, nil
+		}
+
+		choices, err := handler(ctx, itx)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.set(key, choices)
+		return choices, nil
+	}
+}
+
+func (cache *AutoCompleteCache) get(key string) ([]CommandOptionChoice, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || time.Now().After(entry.expire) {
+		return nil, false
+	}
+	return entry.choices, true
+}
+
+func (cache *AutoCompleteCache) set(key string, choices []CommandOptionChoice) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(cache.lastSweep) > cache.ttl {
+		for key, entry := range cache.entries {
+			if now.After(entry.expire) {
+				delete(cache.entries, key)
+			}
+		}
+		cache.lastSweep = now
+	}
+
+	cache.entries[key] = autoCompleteCacheEntry{expire: now.Add(cache.ttl), choices: choices}
+}
+
+func autoCompleteCacheKey(itx CommandInteraction) string {
+	var sb strings.Builder
+	if user := itx.BaseUser(); user != nil {
+		sb.WriteString(user.ID.String())
+	}
+
+	sb.WriteByte('|')
+	sb.WriteString(itx.Data.Name)
+	for _, option := range itx.Data.Options {
+		fmt.Fprintf(&sb, "|%s=%v", option.Name, option.Value)
+		if option.Focused {
+			sb.WriteByte('*')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package tempest
+
+import "testing"
+
+func TestRankChoices(t *testing.T) {
+	candidates := []string{"Dark Souls", "Darkest Dungeon", "Stardew Valley", "Hollow Knight", "Żółw Ninja", "Dark", "Minecraft"}
+
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{"dark", []string{"Dark", "Dark Souls", "Darkest Dungeon"}},
+		{"knight", []string{"Hollow Knight"}},
+		{"dew", []string{"Stardew Valley"}},
+		{"zolw", []string{"Żółw Ninja"}},
+		{"minecarft", []string{"Minecraft"}},
+		{"xyz", nil},
+	}
+
+	for _, tc := range cases {
+		choices := RankStrings(tc.query, candidates, ENGLISH_US_LANGUAGE)
+		if len(choices) != len(tc.expected) {
+			t.Errorf("%q: expected %d choices, got %+v", tc.query, len(tc.expected), choices)
+			continue
+		}
+
+		for i, name := range tc.expected {
+			if choices[i].Name != name {
+				t.Errorf("%q: expected %q at position %d, got %q", tc.query, name, i, choices[i].Name)
+			}
+		}
+	}
+
+	many := make([]string, 40)
+	for i := range many {
+		many[i] = "item"
+	}
+
+	if choices := RankStrings("it", many, ENGLISH_US_LANGUAGE); len(choices) != MAX_AUTO_COMPLETE_CHOICES {
+		t.Errorf("expected results to be capped at %d, got %d", MAX_AUTO_COMPLETE_CHOICES, len(choices))
+	}
+
+	if choices := RankStrings("ılık", []string{"Ilık"}, TURKISH_LANGUAGE); len(choices) != 1 {
+		t.Error("expected Turkish dotless i to match with Turkish locale")
+	}
+}
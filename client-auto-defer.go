@@ -0,0 +1,170 @@
+package tempest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Decides whether (and how) client defers command when its handler doesn't respond in time.
+type AutoDeferMode uint8
+
+const (
+	INHERIT_AUTO_DEFER_MODE   AutoDeferMode = iota // Use client's AutoDeferOptions.
+	PUBLIC_AUTO_DEFER_MODE                         // Defer with "Bot is thinking..." message visible to everyone.
+	EPHEMERAL_AUTO_DEFER_MODE                      // Defer with "Bot is thinking..." message visible only to the user.
+	DISABLED_AUTO_DEFER_MODE                       // Never defer this command automatically.
+)
+
+// Discord marks interaction as failed when it receives no response within 3 seconds.
+// With auto defer enabled, client sends deferred response on its own once deadline passes
+// and turns all later replies into edits of the original response (or follow-ups when appropriate).
+//
+// Commands get DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE response while components and modals get DEFERRED_UPDATE_MESSAGE.
+// Timer only runs while handler does - dropped interactions and handlers that return without responding are never deferred.
+//
+// Discord ignores ephemeral flag when editing original response, so when command got public "thinking" message and handler
+// later sends ephemeral reply - client deletes that message and sends reply as ephemeral follow-up instead.
+type AutoDeferOptions struct {
+	Deadline  time.Duration // How long client waits for handler's own response. By default: 2 seconds (capped at 2.3 seconds).
+	Enabled   bool          // Whether to enable auto defer for all interactions. Commands can still opt-in/out individually with Command.AutoDefer.
+	Ephemeral bool          // Whether deferred command response should be visible only to the user.
+}
+
+const maxAutoDeferDeadline = 2300 * time.Millisecond // HTTP client gives up on response after 2.5s.
+
+func (opt AutoDeferOptions) deadline() time.Duration {
+	if opt.Deadline <= 0 {
+		return 2 * time.Second
+	}
+	return min(opt.Deadline, maxAutoDeferDeadline)
+}
+
+// Arms auto defer timer for command interaction, according to client's & command's settings.
+func (client *BaseClient) autoDeferCommand(itx *Interaction, mode AutoDeferMode) {
+	if mode == INHERIT_AUTO_DEFER_MODE {
+		if !client.autoDefer.Enabled {
+			return
+		}
+
+		mode = PUBLIC_AUTO_DEFER_MODE
+		if client.autoDefer.Ephemeral {
+			mode = EPHEMERAL_AUTO_DEFER_MODE
+		}
+	}
+
+	if mode == DISABLED_AUTO_DEFER_MODE {
+		return
+	}
+
+	var flags MessageFlags = 0
+	if mode == EPHEMERAL_AUTO_DEFER_MODE {
+		flags = EPHEMERAL_MESSAGE_FLAG
+	}
+
+	client.armAutoDefer(itx, Response{
+		Type: DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
+		Data: &ResponseMessageData{Flags: flags},
+	})
+}
+
+// Arms auto defer timer for component or modal interaction.
+func (client *BaseClient) autoDeferUpdate(itx *Interaction) {
+	if client.autoDefer.Enabled {
+		client.armAutoDefer(itx, Response{Type: DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE})
+	}
+}
+
+// Stops pending auto defer timer. Called once handler returns, so interactions that handler chose not to answer aren't deferred.
+func (client *BaseClient) disarmAutoDefer(itx *Interaction) {
+	itx.mu.Lock()
+	defer itx.mu.Unlock()
+
+	if itx.autoDeferTimer != nil {
+		itx.autoDeferTimer.Stop()
+	}
+}
+
+func (client *BaseClient) armAutoDefer(itx *Interaction, res Response) {
+	itx.mu.Lock()
+	defer itx.mu.Unlock()
+
+	if itx.responded || itx.deferred || itx.autoDeferTimer != nil {
+		return
+	}
+
+	itx.autoDeferTimer = time.AfterFunc(client.autoDefer.deadline(), func() {
+		itx.mu.Lock()
+		defer itx.mu.Unlock()
+
+		if itx.responded || itx.deferred {
+			return
+		}
+
+		if err := itx.responder(res); err != nil {
+			client.tracef("Failed to automatically defer interaction (ID = %s): %v", itx.ID.String(), err)
+			return
+		}
+
+		client.tracef("Interaction (ID = %s) was automatically deferred as its handler didn't respond in time.", itx.ID.String())
+		itx.deferred = true
+		itx.autoDeferred = true
+		itx.deferredUpdate = res.Type == DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE
+		if data, ok := res.Data.(*ResponseMessageData); ok && res.Type == DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE {
+			itx.autoDeferPublic = data.Flags&EPHEMERAL_MESSAGE_FLAG == 0
+		}
+	})
+}
+
+// Turns initial response into its REST equivalent, for interaction that was automatically deferred by client.
+// Caller must hold the lock.
+func (itx *Interaction) respondAfterAutoDeferLocked(res Response) error {
+	endpoint := itx.webhookEndpoint()
+
+	switch res.Type {
+	case DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE, DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE:
+		return nil // Already deferred.
+	case CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE:
+		if data, ok := res.Data.(*ResponseMessageData); ok && itx.autoDeferPublic && data.Flags&EPHEMERAL_MESSAGE_FLAG != 0 {
+			err := itx.replacePublicAutoDefer(*data, res.Files)
+			if err == nil {
+				itx.responded = true
+			}
+			return err
+		}
+
+		var err error
+		if itx.deferredUpdate {
+			_, err = itx.BaseClient.Rest.Request(http.MethodPost, endpoint, res.Data)
+		} else {
+			_, err = itx.BaseClient.Rest.Request(http.MethodPatch, endpoint+"/messages/@original", res.Data)
+		}
+
+		if err == nil {
+			itx.responded = true
+		}
+		return err
+	case UPDATE_MESSAGE_RESPONSE_TYPE:
+		_, err := itx.BaseClient.Rest.Request(http.MethodPatch, endpoint+"/messages/@original", res.Data)
+		if err == nil {
+			itx.responded = true
+		}
+		return err
+	case MODAL_RESPONSE_TYPE:
+		return errors.New("cannot open modal - interaction was already automatically deferred by client (consider disabling auto defer for this command)")
+	}
+
+	return fmt.Errorf("response type %d cannot be sent after interaction was automatically deferred", res.Type)
+}
+
+// Sends ephemeral reply to interaction that was automatically deferred with public "thinking" message.
+// Editing original response would make reply public, so that message is deleted and reply goes out as ephemeral follow-up.
+func (itx *Interaction) replacePublicAutoDefer(reply ResponseMessageData, files []File) error {
+	if _, err := itx.BaseClient.Rest.Request(http.MethodDelete, itx.webhookEndpoint()+"/messages/@original", nil); err != nil {
+		return err
+	}
+
+	_, err := itx.SendFollowUpWithFiles(reply, true, files)
+	return err
+}
@@ -0,0 +1,106 @@
+package tempest
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAutoDeferOnlyWhileHandlerRuns(t *testing.T) {
+	client := &BaseClient{autoDefer: AutoDeferOptions{Enabled: true, Deadline: 10 * time.Millisecond}}
+
+	var deferred atomic.Int32
+	newInteraction := func() *ComponentInteraction {
+		return &ComponentInteraction{Interaction: &Interaction{
+			Type: MESSAGE_COMPONENT_INTERACTION_TYPE,
+			responder: func(res Response) error {
+				if res.Type == DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE {
+					deferred.Add(1)
+				}
+				return nil
+			},
+		}}
+	}
+
+	client.runComponent(newInteraction(), func(*ComponentInteraction) {})
+	time.Sleep(30 * time.Millisecond)
+	if deferred.Load() != 0 {
+		t.Fatal("expected handler that returned without responding to never be deferred")
+	}
+
+	client.runComponent(newInteraction(), func(*ComponentInteraction) { time.Sleep(30 * time.Millisecond) })
+	if deferred.Load() != 1 {
+		t.Fatalf("expected slow handler to be deferred once, got %d", deferred.Load())
+	}
+}
+
+func TestEphemeralReplyAfterPublicAutoDefer(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	client := &BaseClient{autoDefer: AutoDeferOptions{Enabled: true, Deadline: 10 * time.Millisecond}}
+	client.Rest = newStubRest(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		calls = append(calls, req.Method+" "+req.URL.Path)
+		mu.Unlock()
+
+		if req.Method == http.MethodDelete {
+			return stubResponse(http.StatusNoContent, ""), nil
+		}
+		return stubResponse(http.StatusOK, `{}`), nil
+	})
+
+	replies := []struct {
+		name  string
+		mode  AutoDeferMode
+		reply func(itx *Interaction) error
+		calls []string
+	}{
+		{
+			name:  "SendReply after public defer",
+			mode:  PUBLIC_AUTO_DEFER_MODE,
+			reply: func(itx *Interaction) error { return itx.SendLinearReply("secret", true) },
+			calls: []string{"DELETE /api/v10/webhooks/42/token/messages/@original", "POST /api/v10/webhooks/42/token"},
+		},
+		{
+			name: "Respond after public defer",
+			mode: PUBLIC_AUTO_DEFER_MODE,
+			reply: func(itx *Interaction) error {
+				return itx.Respond(Response{Type: CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE, Data: &ResponseMessageData{Flags: EPHEMERAL_MESSAGE_FLAG}})
+			},
+			calls: []string{"DELETE /api/v10/webhooks/42/token/messages/@original", "POST /api/v10/webhooks/42/token"},
+		},
+		{
+			name:  "SendReply after ephemeral defer",
+			mode:  EPHEMERAL_AUTO_DEFER_MODE,
+			reply: func(itx *Interaction) error { return itx.SendLinearReply("secret", true) },
+			calls: []string{"PATCH /api/v10/webhooks/42/token/messages/@original"},
+		},
+	}
+
+	for _, tc := range replies {
+		calls = nil
+		itx := &Interaction{
+			BaseClient:    client,
+			ApplicationID: 42,
+			Token:         "token",
+			Type:          APPLICATION_COMMAND_INTERACTION_TYPE,
+			responder:     func(Response) error { return nil },
+		}
+
+		client.autoDeferCommand(itx, tc.mode)
+		time.Sleep(30 * time.Millisecond)
+
+		if err := tc.reply(itx); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		mu.Lock()
+		if !slices.Equal(calls, tc.calls) {
+			t.Errorf("%s: expected requests %v, got %v", tc.name, tc.calls, calls)
+		}
+		mu.Unlock()
+	}
+}
@@ -0,0 +1,134 @@
+package tempest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// https://docs.discord.com/developers/interactions/application-commands#application-command-permissions-object-application-command-permission-type
+type ApplicationCommandPermissionType uint8
+
+const (
+	ROLE_APPLICATION_COMMAND_PERMISSION_TYPE ApplicationCommandPermissionType = iota + 1
+	USER_APPLICATION_COMMAND_PERMISSION_TYPE
+	CHANNEL_APPLICATION_COMMAND_PERMISSION_TYPE
+)
+
+const maxApplicationCommandPermissions = 100 // Per command, per guild.
+
+// Single permission overwrite that allows or denies command usage for role, user or channel.
+//
+// https://docs.discord.com/developers/interactions/application-commands#application-command-permissions-object-application-command-permissions-structure
+type ApplicationCommandPermission struct {
+	ID         Snowflake                        `json:"id"` // ID of role, user or channel. Use EveryonePermissionTarget or AllChannelsPermissionTarget for guild-wide overwrites.
+	Type       ApplicationCommandPermissionType `json:"type"`
+	Permission bool                             `json:"permission"` // True to allow, false to disallow.
+}
+
+// Permission overwrites of single command (or whole app, when ID equals app ID) in guild.
+// Overwrites are applied on top of Command.RequiredPermissions, which only sets defaults for members.
+//
+// https://docs.discord.com/developers/interactions/application-commands#application-command-permissions-object-guild-application-command-permissions-structure
+type GuildApplicationCommandPermissions struct {
+	Permissions   []ApplicationCommandPermission `json:"permissions"`
+	ID            Snowflake                      `json:"id"` // ID of the command or the application ID (for app-wide overwrites).
+	ApplicationID Snowflake                      `json:"application_id"`
+	GuildID       Snowflake                      `json:"guild_id"`
+}
+
+// Returns ID that targets @everyone role in permission overwrites (it's equal to guild ID).
+func EveryonePermissionTarget(guildID Snowflake) Snowflake {
+	return guildID
+}
+
+// Returns ID that targets all channels in guild in permission overwrites (it's equal to guild ID - 1).
+func AllChannelsPermissionTarget(guildID Snowflake) Snowflake {
+	return guildID - 1
+}
+
+// Fetches permission overwrites of all commands (and app-wide overwrites) in guild.
+//
+// https://docs.discord.com/developers/interactions/application-commands#get-guild-application-command-permissions
+func (client *BaseClient) FetchGuildCommandPermissions(guildID Snowflake) ([]GuildApplicationCommandPermissions, error) {
+	raw, err := client.Rest.Request(http.MethodGet, client.commandsEndpoint(guildID)+"/permissions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]GuildApplicationCommandPermissions, 0)
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return nil, errors.New("failed to parse received data from discord")
+	}
+
+	client.tracef("Successfully fetched command permissions for %d command(s) (guild ID = %d).", len(res), guildID)
+	return res, nil
+}
+
+// Fetches permission overwrites of single command in guild.
+// Discord responds with "unknown application command permissions" error when command has no overwrites in this guild.
+// Use app ID as command ID to fetch app-wide overwrites.
+//
+// https://docs.discord.com/developers/interactions/application-commands#get-application-command-permissions
+func (client *BaseClient) FetchCommandPermissions(guildID Snowflake, commandID Snowflake) (GuildApplicationCommandPermissions, error) {
+	raw, err := client.Rest.Request(http.MethodGet, client.commandsEndpoint(guildID)+"/"+commandID.String()+"/permissions", nil)
+	if err != nil {
+		return GuildApplicationCommandPermissions{}, err
+	}
+
+	res := GuildApplicationCommandPermissions{}
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return GuildApplicationCommandPermissions{}, errors.New("failed to parse received data from discord")
+	}
+
+	return res, nil
+}
+
+// Overwrites all permissions of single command in guild (pass app ID as command ID to edit app-wide overwrites).
+// Discord doesn't accept bot token for this endpoint - provide OAuth2 access token of guild member that can manage
+// guild & roles, with "applications.commands.permissions.update" scope ("Bearer " prefix is optional).
+//
+// Use [BaseClient.FindCommand] or [BaseClient.FindCommandByID] to get command ID after syncing commands.
+//
+// https://docs.discord.com/developers/interactions/application-commands#edit-application-command-permissions
+func (client *BaseClient) EditCommandPermissions(bearerToken string, guildID Snowflake, commandID Snowflake, permissions []ApplicationCommandPermission) (GuildApplicationCommandPermissions, error) {
+	if bearerToken == "" {
+		return GuildApplicationCommandPermissions{}, errors.New("editing command permissions requires bearer token (bot token is not accepted by Discord)")
+	}
+
+	if len(permissions) > maxApplicationCommandPermissions {
+		return GuildApplicationCommandPermissions{}, errors.New("too many command permission overwrites (" + strconv.Itoa(len(permissions)) + "/" + strconv.Itoa(maxApplicationCommandPermissions) + ")")
+	}
+
+	if !strings.HasPrefix(bearerToken, "Bearer ") {
+		bearerToken = "Bearer " + bearerToken
+	}
+
+	if permissions == nil {
+		permissions = make([]ApplicationCommandPermission, 0)
+	}
+
+	payload := struct {
+		Permissions []ApplicationCommandPermission `json:"permissions"`
+	}{permissions}
+
+	raw, err := client.Rest.RequestWithOptions(http.MethodPut, client.commandsEndpoint(guildID)+"/"+commandID.String()+"/permissions", payload, RequestOptions{
+		Authorization: bearerToken,
+	})
+	if err != nil {
+		return GuildApplicationCommandPermissions{}, err
+	}
+
+	res := GuildApplicationCommandPermissions{}
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return GuildApplicationCommandPermissions{}, errors.New("failed to parse received data from discord")
+	}
+
+	client.tracef("Successfully edited permissions of command (ID = %d, guild ID = %d).", commandID, guildID)
+	return res, nil
+}
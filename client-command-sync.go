@@ -0,0 +1,339 @@
+package tempest
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// Describes what incremental sync does (or would do in dry run) with single command.
+type CommandSyncAction uint8
+
+const (
+	UNCHANGED_COMMAND_SYNC_ACTION CommandSyncAction = iota // Remote command already matches local definition.
+	CREATE_COMMAND_SYNC_ACTION                             // Command exists only locally and will be created.
+	UPDATE_COMMAND_SYNC_ACTION                             // Command exists on both sides but its definition differs.
+	DELETE_COMMAND_SYNC_ACTION                             // Command exists only on Discord's side and will be deleted.
+)
+
+func (action CommandSyncAction) String() string {
+	switch action {
+	case CREATE_COMMAND_SYNC_ACTION:
+		return "create"
+	case UPDATE_COMMAND_SYNC_ACTION:
+		return "update"
+	case DELETE_COMMAND_SYNC_ACTION:
+		return "delete"
+	}
+	return "unchanged"
+}
+
+type CommandSyncOptions struct {
+	GuildIDs    []Snowflake // Guilds to sync commands with. Leave empty to sync global commands.
+	Whitelist   []string    // Names of root commands to include (or exclude, in reverse mode). Leave empty to include all registered commands. Remote commands filtered out by it are never deleted.
+	ReverseMode bool        // Whether to treat whitelist as blacklist.
+	DryRun      bool        // Only compute & return plan, without applying any changes.
+	KeepUnknown bool        // Whether to keep remote commands that pass whitelist but aren't registered locally, instead of deleting them.
+}
+
+// Single entry of incremental sync plan.
+type CommandSyncChange struct {
+	Local   Command // Local definition (as sent to Discord). Empty for deleted commands.
+	Remote  Command // Remote command before sync. Empty for created commands.
+	Name    string
+	GuildID Snowflake // Scope of change. Equals 0 for global commands.
+	Type    CommandType
+	Action  CommandSyncAction
+}
+
+// Fetches commands currently registered on Discord's side, together with their localizations.
+// Use guildID = 0 to fetch global commands. IDs of commands that are also registered locally are stored in registry.
+//
+// https://docs.discord.com/developers/interactions/application-commands#get-global-application-commands
+func (client *BaseClient) FetchCommands(guildID Snowflake) ([]Command, error) {
+	raw, err := client.Rest.Request(http.MethodGet, client.commandsEndpoint(guildID)+"?with_localizations=true", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Command, 0)
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return nil, errors.New("failed to parse received data from discord")
+	}
+
+	client.storeCommandIDs(res)
+	client.tracef("Successfully fetched %d command(s) (guild ID = %d).", len(res), guildID)
+	return res, nil
+}
+
+// Compares local commands with ones registered on Discord's side and applies only needed changes,
+// instead of bulk overwriting everything like [BaseClient.SyncCommandsWithDiscord].
+// Fields that are set by Discord (ID, version, application ID) are ignored during comparison.
+//
+// It returns full plan (for every synced scope), even in dry run mode.
+// When sync fails half way, plan is returned together with error and only changes before failing one were applied.
+//
+// IDs & versions of created, updated and unchanged commands are stored back into registry.
+// Discord assigns different IDs for each scope, so when syncing multiple guilds - registry keeps IDs from the last one.
+func (client *BaseClient) SyncCommands(opt CommandSyncOptions) ([]CommandSyncChange, error) {
+	local := parseCommandsForDiscordAPI(client.commands, opt.Whitelist, opt.ReverseMode)
+	if errs := validateCommandSet(local, false); len(errs) != 0 {
+		return nil, errs
+	}
+
+	slices.SortFunc(local, compareCommands)
+
+	scopes := opt.GuildIDs
+	if len(scopes) == 0 {
+		scopes = []Snowflake{0}
+	}
+
+	plan := make([]CommandSyncChange, 0, len(local)*len(scopes))
+	for _, guildID := range scopes {
+		remote, err := client.FetchCommands(guildID)
+		if err != nil {
+			return plan, err
+		}
+
+		changes := diffCommands(guildID, local, remote, opt)
+		plan = append(plan, changes...)
+
+		if opt.DryRun {
+			continue
+		}
+
+		if err := client.applyCommandChanges(changes); err != nil {
+			return plan, err
+		}
+	}
+
+	if !opt.DryRun {
+		client.tracef("Successfully synced command data with discord (incremental sync).")
+	}
+
+	return plan, nil
+}
+
+func (client *BaseClient) applyCommandChanges(changes []CommandSyncChange) error {
+	synced := make([]Command, 0, len(changes))
+
+	for _, change := range changes {
+		endpoint := client.commandsEndpoint(change.GuildID)
+
+		var raw []byte
+		var err error
+		switch change.Action {
+		case UNCHANGED_COMMAND_SYNC_ACTION:
+			synced = append(synced, change.Remote)
+			continue
+		case CREATE_COMMAND_SYNC_ACTION:
+			raw, err = client.Rest.Request(http.MethodPost, endpoint, change.Local)
+		case UPDATE_COMMAND_SYNC_ACTION:
+			raw, err = client.Rest.Request(http.MethodPatch, endpoint+"/"+change.Remote.ID.String(), change.Local)
+		case DELETE_COMMAND_SYNC_ACTION:
+			_, err = client.Rest.Request(http.MethodDelete, endpoint+"/"+change.Remote.ID.String(), nil)
+			if err != nil {
+				return err
+			}
+
+			client.tracef("Deleted %s command (guild ID = %d).", change.Name, change.GuildID)
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var res Command
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return errors.New("failed to parse received data from discord")
+		}
+
+		synced = append(synced, res)
+		client.tracef("Successfully applied %s action to %s command (guild ID = %d).", change.Action, change.Name, change.GuildID)
+	}
+
+	client.storeCommandIDs(synced)
+	return nil
+}
+
+// Saves IDs & versions of remote commands into matching registry entries and indexes them for lookup by ID.
+func (client *BaseClient) storeCommandIDs(remote []Command) {
+	client.commands.mu.Lock()
+	defer client.commands.mu.Unlock()
+
+	for _, cmd := range remote {
+		key := commandRegistryKey(cmd.Type, cmd.Name)
+		entry, ok := client.commands.cache[key]
+		if !ok || entry.Type != cmd.Type {
+			continue
+		}
+
+		entry.ID, entry.Version = cmd.ID, cmd.Version
+		client.commands.cache[key] = entry
+		client.commandIDs.Set(cmd.ID, key)
+	}
+}
+
+func (client *BaseClient) commandsEndpoint(guildID Snowflake) string {
+	if guildID == 0 {
+		return "/applications/" + client.ApplicationID.String() + "/commands"
+	}
+	return "/applications/" + client.ApplicationID.String() + "/guilds/" + guildID.String() + "/commands"
+}
+
+// Builds sync plan for single scope. Local commands must be sorted with compareCommands.
+// Remote commands outside of whitelist are left alone, so syncing selected commands never wipes the rest.
+func diffCommands(guildID Snowflake, local []Command, remote []Command, opt CommandSyncOptions) []CommandSyncChange {
+	type commandKey struct {
+		name string
+		kind CommandType
+	}
+
+	remoteByKey := make(map[commandKey]Command, len(remote))
+	for _, cmd := range remote {
+		remoteByKey[commandKey{cmd.Name, cmd.Type}] = cmd
+	}
+
+	changes := make([]CommandSyncChange, 0, len(local)+len(remote))
+	for _, cmd := range local {
+		change := CommandSyncChange{Local: cmd, Name: cmd.Name, GuildID: guildID, Type: cmd.Type, Action: CREATE_COMMAND_SYNC_ACTION}
+
+		key := commandKey{cmd.Name, cmd.Type}
+		if existing, ok := remoteByKey[key]; ok {
+			delete(remoteByKey, key)
+			change.Remote = existing
+			change.Action = UPDATE_COMMAND_SYNC_ACTION
+			if commandsEqual(cmd, existing) {
+				change.Action = UNCHANGED_COMMAND_SYNC_ACTION
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	if opt.KeepUnknown {
+		return changes
+	}
+
+	deleted := make([]Command, 0, len(remoteByKey))
+	for _, cmd := range remoteByKey {
+		if len(opt.Whitelist) != 0 && slices.Contains(opt.Whitelist, cmd.Name) == opt.ReverseMode {
+			continue
+		}
+		deleted = append(deleted, cmd)
+	}
+
+	slices.SortFunc(deleted, compareCommands)
+	for _, cmd := range deleted {
+		changes = append(changes, CommandSyncChange{Remote: cmd, Name: cmd.Name, GuildID: guildID, Type: cmd.Type, Action: DELETE_COMMAND_SYNC_ACTION})
+	}
+
+	return changes
+}
+
+func compareCommands(a, b Command) int {
+	return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Name, b.Name))
+}
+
+// Part of command definition that's controlled by app. Everything else is either set by Discord or has no effect on command.
+type commandSignature struct {
+	NameLocalizations        map[Language]string          `json:"name_localizations,omitempty"`
+	DescriptionLocalizations map[Language]string          `json:"description_localizations,omitempty"`
+	Name                     string                       `json:"name"`
+	Description              string                       `json:"description"`
+	Options                  []CommandOption              `json:"options,omitempty"`
+	IntegrationTypes         []ApplicationIntegrationType `json:"integration_types,omitempty"`
+	Contexts                 []InteractionContextType     `json:"contexts,omitempty"`
+	RequiredPermissions      PermissionFlags              `json:"default_member_permissions"`
+	NSFW                     bool                         `json:"nsfw"`
+	Handler                  CommandHandlerType           `json:"handler"`
+	Type                     CommandType                  `json:"type"`
+}
+
+func commandsEqual(local Command, remote Command) bool {
+	localSignature, remoteSignature := signCommand(local), signCommand(remote)
+
+	// Discord fills missing fields with its defaults - only compare ones that were explicitly set locally.
+	if len(local.IntegrationTypes) == 0 {
+		localSignature.IntegrationTypes = nil
+		remoteSignature.IntegrationTypes = nil
+	}
+
+	if len(local.Contexts) == 0 {
+		localSignature.Contexts = nil
+		remoteSignature.Contexts = nil
+	}
+
+	a, errA := json.Marshal(localSignature)
+	b, errB := json.Marshal(remoteSignature)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+func signCommand(cmd Command) commandSignature {
+	if cmd.Type == 0 {
+		cmd.Type = CHAT_INPUT_COMMAND_TYPE
+	}
+
+	return commandSignature{
+		NameLocalizations:        cmd.NameLocalizations,
+		DescriptionLocalizations: cmd.DescriptionLocalizations,
+		Name:                     cmd.Name,
+		Description:              cmd.Description,
+		Options:                  normalizeCommandOptions(cmd.Options),
+		IntegrationTypes:         cmd.IntegrationTypes,
+		Contexts:                 cmd.Contexts,
+		RequiredPermissions:      cmd.RequiredPermissions,
+		NSFW:                     cmd.NSFW,
+		Handler:                  cmd.Handler,
+		Type:                     cmd.Type,
+	}
+}
+
+// Replaces empty collections with nil ones so both local & remote options serialize the same way.
+func normalizeCommandOptions(options []CommandOption) []CommandOption {
+	if len(options) == 0 {
+		return nil
+	}
+
+	normalized := make([]CommandOption, len(options))
+	for i, option := range options {
+		if len(option.NameLocalizations) == 0 {
+			option.NameLocalizations = nil
+		}
+
+		if len(option.DescriptionLocalizations) == 0 {
+			option.DescriptionLocalizations = nil
+		}
+
+		if len(option.ChannelTypes) == 0 {
+			option.ChannelTypes = nil
+		}
+
+		if len(option.FileTypes) == 0 {
+			option.FileTypes = nil
+		}
+
+		if len(option.Choices) == 0 {
+			option.Choices = nil
+		} else {
+			choices := make([]CommandOptionChoice, len(option.Choices))
+			for j, choice := range option.Choices {
+				if len(choice.NameLocalizations) == 0 {
+					choice.NameLocalizations = nil
+				}
+				choices[j] = choice
+			}
+			option.Choices = choices
+		}
+
+		option.Options = normalizeCommandOptions(option.Options)
+		normalized[i] = option
+	}
+
+	return normalized
+}
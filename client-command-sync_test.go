@@ -0,0 +1,105 @@
+package tempest
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffCommands(t *testing.T) {
+	local := []Command{
+		{Name: "ping", Description: "Pong.", Type: CHAT_INPUT_COMMAND_TYPE},
+		{Name: "stats", Description: "Shows new stats.", Type: CHAT_INPUT_COMMAND_TYPE},
+		{Name: "create", Description: "New command.", Type: CHAT_INPUT_COMMAND_TYPE},
+	}
+	slices.SortFunc(local, compareCommands)
+
+	remote := []Command{
+		{ID: 1, Version: 1, ApplicationID: 9, Name: "ping", Description: "Pong.", Type: CHAT_INPUT_COMMAND_TYPE, IntegrationTypes: []ApplicationIntegrationType{GUILD_INSTALL}},
+		{ID: 2, Name: "stats", Description: "Shows stats.", Type: CHAT_INPUT_COMMAND_TYPE},
+		{ID: 3, Name: "legacy", Description: "Old command.", Type: CHAT_INPUT_COMMAND_TYPE},
+		{ID: 4, Name: "report", Type: USER_COMMAND_TYPE},
+	}
+
+	cases := []struct {
+		name     string
+		opt      CommandSyncOptions
+		expected map[string]CommandSyncAction
+	}{
+		{
+			name: "full sync",
+			expected: map[string]CommandSyncAction{
+				"ping":   UNCHANGED_COMMAND_SYNC_ACTION,
+				"stats":  UPDATE_COMMAND_SYNC_ACTION,
+				"create": CREATE_COMMAND_SYNC_ACTION,
+				"legacy": DELETE_COMMAND_SYNC_ACTION,
+				"report": DELETE_COMMAND_SYNC_ACTION,
+			},
+		},
+		{
+			name: "keep unknown",
+			opt:  CommandSyncOptions{KeepUnknown: true},
+			expected: map[string]CommandSyncAction{
+				"ping":   UNCHANGED_COMMAND_SYNC_ACTION,
+				"stats":  UPDATE_COMMAND_SYNC_ACTION,
+				"create": CREATE_COMMAND_SYNC_ACTION,
+			},
+		},
+		{
+			name: "whitelist leaves other remote commands alone",
+			opt:  CommandSyncOptions{Whitelist: []string{"ping", "stats", "create", "legacy"}},
+			expected: map[string]CommandSyncAction{
+				"ping":   UNCHANGED_COMMAND_SYNC_ACTION,
+				"stats":  UPDATE_COMMAND_SYNC_ACTION,
+				"create": CREATE_COMMAND_SYNC_ACTION,
+				"legacy": DELETE_COMMAND_SYNC_ACTION,
+			},
+		},
+		{
+			name: "blacklist leaves listed remote commands alone",
+			opt:  CommandSyncOptions{Whitelist: []string{"report"}, ReverseMode: true},
+			expected: map[string]CommandSyncAction{
+				"ping":   UNCHANGED_COMMAND_SYNC_ACTION,
+				"stats":  UPDATE_COMMAND_SYNC_ACTION,
+				"create": CREATE_COMMAND_SYNC_ACTION,
+				"legacy": DELETE_COMMAND_SYNC_ACTION,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		changes := diffCommands(7, local, remote, tc.opt)
+
+		got := make(map[string]CommandSyncAction, len(changes))
+		for _, change := range changes {
+			if change.GuildID != 7 {
+				t.Errorf("%s: expected change of %s to be scoped to guild 7, got %d", tc.name, change.Name, change.GuildID)
+			}
+			got[change.Name] = change.Action
+		}
+
+		if len(got) != len(tc.expected) {
+			t.Errorf("%s: expected %d changes, got %v", tc.name, len(tc.expected), got)
+			continue
+		}
+
+		for name, action := range tc.expected {
+			if got[name] != action {
+				t.Errorf("%s: expected %s to be %s, got %s", tc.name, name, action, got[name])
+			}
+		}
+	}
+
+	changes := diffCommands(0, local, remote, CommandSyncOptions{})
+	for _, change := range changes {
+		switch change.Action {
+		case UPDATE_COMMAND_SYNC_ACTION:
+			if change.Remote.ID != 2 || change.Local.Description != "Shows new stats." {
+				t.Errorf("update should carry both definitions, got %+v", change)
+			}
+		case DELETE_COMMAND_SYNC_ACTION:
+			if change.Remote.ID == 0 || change.Local.Name != "" {
+				t.Errorf("delete should only carry remote command, got %+v", change)
+			}
+		}
+	}
+}
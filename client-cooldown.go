@@ -0,0 +1,191 @@
+package tempest
+
+import (
+	"strconv"
+	"time"
+)
+
+// Decides who shares the same cooldown bucket or concurrency slots. Flags can be combined,
+// e.g. USER_LIMIT_SCOPE|GUILD_LIMIT_SCOPE tracks each user separately in every guild.
+// Zero value (GLOBAL_LIMIT_SCOPE) means everyone shares the same limit.
+type LimitScope uint8
+
+const (
+	USER_LIMIT_SCOPE    LimitScope = 1 << iota // Separate limit for each user.
+	GUILD_LIMIT_SCOPE                          // Separate limit for each guild (DMs share single bucket).
+	CHANNEL_LIMIT_SCOPE                        // Separate limit for each channel.
+
+	GLOBAL_LIMIT_SCOPE LimitScope = 0 // Single limit shared by everyone.
+)
+
+// Allows command to be used Rate times within Per duration (token bucket - uses refill gradually).
+// Zero value disables cooldown.
+type Cooldown struct {
+	Rate  uint32
+	Per   time.Duration
+	Scope LimitScope
+}
+
+// Limits how many invocations of command can run at the same time. Zero value disables limit.
+type MaxConcurrency struct {
+	N     uint32
+	Scope LimitScope
+}
+
+// Storage for cooldown buckets. Implement it to share cooldowns between multiple app instances (for example with Redis).
+type CooldownStore interface {
+	// Takes single use from bucket under given key. Returns 0 when use was allowed or how long caller needs to wait otherwise.
+	Take(key string, cooldown Cooldown, now time.Time) time.Duration
+}
+
+type tokenBucket struct {
+	updatedAt time.Time
+	tokens    float64
+	per       time.Duration
+}
+
+// Default, in-memory cooldown store. Buckets that fully refilled are swept periodically.
+type MemoryCooldownStore struct {
+	buckets   *SharedMap[string, *tokenBucket]
+	lastSweep time.Time
+}
+
+const cooldownSweepInterval = time.Minute
+
+func NewMemoryCooldownStore() *MemoryCooldownStore {
+	return &MemoryCooldownStore{buckets: NewSharedMap[string, *tokenBucket]()}
+}
+
+func (store *MemoryCooldownStore) Take(key string, cooldown Cooldown, now time.Time) time.Duration {
+	rate := float64(cooldown.Rate) / float64(cooldown.Per)
+
+	store.buckets.mu.Lock()
+	bucket, ok := store.buckets.cache[key]
+	if !ok {
+		bucket = &tokenBucket{updatedAt: now, tokens: float64(cooldown.Rate), per: cooldown.Per}
+		store.buckets.cache[key] = bucket
+	}
+
+	bucket.tokens = min(float64(cooldown.Rate), bucket.tokens+float64(now.Sub(bucket.updatedAt))*rate)
+	bucket.updatedAt = now
+
+	var wait time.Duration
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+	} else {
+		wait = time.Duration((1 - bucket.tokens) / rate)
+	}
+
+	shouldSweep := now.Sub(store.lastSweep) > cooldownSweepInterval
+	if shouldSweep {
+		store.lastSweep = now
+	}
+	store.buckets.mu.Unlock()
+
+	if shouldSweep {
+		store.buckets.Sweep(func(_ string, bucket *tokenBucket) bool {
+			return now.Sub(bucket.updatedAt) >= bucket.per
+		})
+	}
+
+	return wait
+}
+
+// Builds bucket key for command invocation, according to scope.
+func limitKey(name string, scope LimitScope, itx *Interaction) string {
+	key := name
+	if scope&USER_LIMIT_SCOPE != 0 {
+		var userID Snowflake
+		if user := itx.BaseUser(); user != nil {
+			userID = user.ID
+		}
+		key += ":u" + userID.String()
+	}
+
+	if scope&GUILD_LIMIT_SCOPE != 0 {
+		key += ":g" + itx.GuildID.String()
+	}
+
+	if scope&CHANNEL_LIMIT_SCOPE != 0 {
+		key += ":c" + itx.ChannelID.String()
+	}
+
+	return key
+}
+
+// Enforces command's cooldown & concurrency limit. It's the innermost middleware of each command.
+// Concurrency is checked first, so invocations rejected at max concurrency don't use up cooldown.
+func (client *BaseClient) limitsMiddleware(next Handler) Handler {
+	return func(inv *Invocation) error {
+		meta := inv.Meta
+
+		if meta.MaxConcurrency.N != 0 {
+			key := limitKey(inv.Name, meta.MaxConcurrency.Scope, inv.Interaction)
+			if !client.acquireConcurrency(key, meta.MaxConcurrency.N) {
+				client.tracef("Command %s reached max concurrency for interaction (ID = %s).", inv.Name, inv.Interaction.ID.String())
+				client.onMaxConcurrency(inv.Command)
+				return nil
+			}
+			defer client.releaseConcurrency(key)
+		}
+
+		if meta.Cooldown.Rate != 0 && meta.Cooldown.Per > 0 {
+			wait := client.cooldownStore.Take(limitKey(inv.Name, meta.Cooldown.Scope, inv.Interaction), meta.Cooldown, time.Now())
+			if wait > 0 {
+				client.tracef("Command %s is on cooldown for interaction (ID = %s), retry after %v.", inv.Name, inv.Interaction.ID.String(), wait)
+				client.onCooldown(inv.Command, wait)
+				return nil
+			}
+		}
+
+		return next(inv)
+	}
+}
+
+func (client *BaseClient) acquireConcurrency(key string, limit uint32) bool {
+	client.concurrency.mu.Lock()
+	defer client.concurrency.mu.Unlock()
+
+	if client.concurrency.cache[key] >= limit {
+		return false
+	}
+
+	client.concurrency.cache[key]++
+	return true
+}
+
+func (client *BaseClient) releaseConcurrency(key string) {
+	client.concurrency.mu.Lock()
+	defer client.concurrency.mu.Unlock()
+
+	if client.concurrency.cache[key] <= 1 {
+		delete(client.concurrency.cache, key)
+		return
+	}
+	client.concurrency.cache[key]--
+}
+
+func (client *BaseClient) onCooldown(itx *CommandInteraction, retryAfter time.Duration) {
+	if client.cooldownHandler != nil {
+		client.cooldownHandler(itx, retryAfter)
+		return
+	}
+
+	// Discord renders relative timestamp in user's own language.
+	retryAt := time.Now().Add(retryAfter).Add(time.Second - 1).Unix()
+	err := itx.SendLinearReply("This command is on cooldown. You can use it again <t:"+strconv.FormatInt(retryAt, 10)+":R>.", true)
+	if err != nil {
+		client.tracef("Failed to send cooldown reply: %v", err)
+	}
+}
+
+func (client *BaseClient) onMaxConcurrency(itx *CommandInteraction) {
+	if client.maxConcurrencyHandler != nil {
+		client.maxConcurrencyHandler(itx)
+		return
+	}
+
+	if err := itx.SendLinearReply("This command is already running too many times. Please wait for it to finish.", true); err != nil {
+		client.tracef("Failed to send max concurrency reply: %v", err)
+	}
+}
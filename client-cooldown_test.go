@@ -0,0 +1,66 @@
+package tempest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCooldownStore(t *testing.T) {
+	store := NewMemoryCooldownStore()
+	cooldown := Cooldown{Rate: 2, Per: 10 * time.Second, Scope: USER_LIMIT_SCOPE}
+	now := time.Now()
+
+	for i := range 2 {
+		if wait := store.Take("ping:u1", cooldown, now); wait != 0 {
+			t.Fatalf("use #%d should be allowed, got wait = %v", i+1, wait)
+		}
+	}
+
+	if wait := store.Take("ping:u1", cooldown, now); wait != 5*time.Second {
+		t.Errorf("expected 5s wait for third use, got %v", wait)
+	}
+
+	if wait := store.Take("ping:u2", cooldown, now); wait != 0 {
+		t.Errorf("other user should have own bucket, got wait = %v", wait)
+	}
+
+	if wait := store.Take("ping:u1", cooldown, now.Add(5*time.Second)); wait != 0 {
+		t.Errorf("single use should refill after 5s, got wait = %v", wait)
+	}
+}
+
+func TestLimitsMiddlewareKeepsCooldownOnConcurrencyReject(t *testing.T) {
+	client := &BaseClient{cooldownStore: NewMemoryCooldownStore(), concurrency: NewSharedMap[string, uint32]()}
+
+	var rejected, calls int
+	client.maxConcurrencyHandler = func(*CommandInteraction) { rejected++ }
+	client.cooldownHandler = func(*CommandInteraction, time.Duration) { t.Error("rejected invocation should not use up cooldown") }
+
+	handler := client.limitsMiddleware(func(*Invocation) error {
+		calls++
+		return nil
+	})
+
+	itx := &Interaction{User: &User{ID: 1}}
+	inv := &Invocation{
+		Interaction: itx,
+		Command:     &CommandInteraction{Interaction: itx},
+		Meta:        Command{Cooldown: Cooldown{Rate: 1, Per: time.Minute, Scope: USER_LIMIT_SCOPE}, MaxConcurrency: MaxConcurrency{N: 1, Scope: USER_LIMIT_SCOPE}},
+		Name:        "ping",
+	}
+
+	key := limitKey(inv.Name, USER_LIMIT_SCOPE, itx)
+	client.acquireConcurrency(key, 1) // Another invocation is still running.
+	if err := handler(inv); err != nil || rejected != 1 || calls != 0 {
+		t.Fatalf("expected max concurrency rejection, got err = %v, rejected = %d, calls = %d", err, rejected, calls)
+	}
+
+	client.releaseConcurrency(key)
+	if err := handler(inv); err != nil || calls != 1 {
+		t.Fatalf("expected handler to run once slot got free, got err = %v, calls = %d", err, calls)
+	}
+
+	if client.concurrency.Has(key) {
+		t.Error("expected concurrency slot to be released after handler returned")
+	}
+}
@@ -0,0 +1,102 @@
+package tempest
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Error created from panic that was recovered by client while running interaction handler (or its middleware).
+type PanicError struct {
+	Value any    // Value passed to panic.
+	Stack []byte // Stack trace of goroutine at the moment of panic.
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", err.Value)
+}
+
+// Returns underlying error when code panicked with error value, so errors.Is/As can look through panics.
+func (err *PanicError) Unwrap() error {
+	if inner, ok := err.Value.(error); ok {
+		return inner
+	}
+	return nil
+}
+
+// Text of ephemeral reply that default error handler sends when interaction wasn't answered.
+const defaultErrorReplyContent = "Something went wrong while processing this interaction. Please try again later."
+
+// Runs handler, turning panics into [PanicError].
+func invokeHandler(handler Handler, inv *Invocation) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return handler(inv)
+}
+
+// Passes error returned by (or recovered from) handler to configured error handler.
+func (client *BaseClient) handleError(itx *Interaction, err error) {
+	if client.errorHandler != nil {
+		client.errorHandler(itx, err)
+		return
+	}
+
+	client.defaultErrorHandler(itx, err)
+}
+
+// Traces error (with stack trace for panics) and replies with ephemeral "something went wrong" message
+// if interaction wasn't answered yet (deferred interactions get their loading message replaced).
+func (client *BaseClient) defaultErrorHandler(itx *Interaction, err error) {
+	if panicErr, ok := err.(*PanicError); ok {
+		client.tracef("Interaction (ID = %s) handler panicked: %v\n%s", itx.ID.String(), panicErr.Value, panicErr.Stack)
+	} else {
+		client.tracef("Interaction (ID = %s) handler returned error: %v", itx.ID.String(), err)
+	}
+
+	if itx.Type == APPLICATION_COMMAND_AUTO_COMPLETE_INTERACTION_TYPE || itx.Responded() {
+		return
+	}
+
+	if replyErr := itx.SendLinearReply(defaultErrorReplyContent, true); replyErr != nil {
+		client.tracef("Failed to send error reply for interaction (ID = %s): %v", itx.ID.String(), replyErr)
+	}
+}
+
+// Runs auto complete handler, recovering from panics (auto complete responds with no choices then).
+func (client *BaseClient) runAutoComplete(itx CommandInteraction, command Command) (choices []CommandOptionChoice) {
+	defer func() {
+		if r := recover(); r != nil {
+			choices = nil
+			client.handleError(itx.Interaction, &PanicError{Value: r, Stack: debug.Stack()})
+		}
+	}()
+
+	if command.AutoCompleteHandlerE == nil {
+		return capChoices(command.AutoCompleteHandler(itx))
+	}
+
+	// Discord waits 3 seconds since interaction was created. Clock skew could make that deadline already passed, so give handler at least a second.
+	deadline := itx.ID.CreationTimestamp().Add(AUTO_COMPLETE_DEADLINE)
+	if minDeadline := time.Now().Add(time.Second); deadline.Before(minDeadline) {
+		deadline = minDeadline
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	choices, err := command.AutoCompleteHandlerE(ctx, itx)
+	if err != nil {
+		client.handleError(itx.Interaction, err)
+		return nil
+	}
+	return capChoices(choices)
+}
+
+func capChoices(choices []CommandOptionChoice) []CommandOptionChoice {
+	return choices[:min(len(choices), MAX_AUTO_COMPLETE_CHOICES)]
+}
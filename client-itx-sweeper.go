@@ -1,153 +1,254 @@
 package tempest
 
 import (
+	"container/heap"
 	"sync"
 	"time"
 )
 
+// Single entry shared by all custom IDs registered within one AwaitComponent call.
 type queuedComponent struct {
 	Handler   func(*ComponentInteraction)
-	Expire    time.Time
 	OnTimeout func()
+	timer     *sweeperTimer
+	customIDs []string
+	ManualAck bool // Whether handler acknowledges interaction on its own (client skips automatic DeferUpdate).
 }
 
+// Single entry shared by all custom IDs registered within one AwaitModal call.
 type queuedModal struct {
 	Handler   func(*ModalInteraction)
-	Expire    time.Time
 	OnTimeout func()
+	timer     *sweeperTimer
+	customIDs []string
+	ManualAck bool // Whether handler acknowledges interaction on its own (client skips automatic DeferUpdate).
 }
 
+type sweeperTimer struct {
+	expire time.Time
+	fire   func()
+	index  int // Position in heap, -1 once timer fired or got cancelled.
+}
+
+// Min-heap of timers ordered by expiry time.
+type sweeperTimerHeap []*sweeperTimer
+
+func (h sweeperTimerHeap) Len() int           { return len(h) }
+func (h sweeperTimerHeap) Less(i, j int) bool { return h[i].expire.Before(h[j].expire) }
+
+func (h sweeperTimerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *sweeperTimerHeap) Push(x any) {
+	timer := x.(*sweeperTimer)
+	timer.index = len(*h)
+	*h = append(*h, timer)
+}
+
+func (h *sweeperTimerHeap) Pop() any {
+	old := *h
+	n := len(old)
+	timer := old[n-1]
+	old[n-1] = nil
+	timer.index = -1
+	*h = old[:n-1]
+	return timer
+}
+
+// Tracks expiry of every dynamic listener with single background goroutine.
+// Scheduling, cancelling and extending timers costs O(log n) and goroutine only wakes up when the earliest timer expires.
 type interactionSweeper struct {
-	lowestTimes [3]time.Time
-	signal      chan struct{}
-	mu          sync.Mutex
-	running     bool
-}
-
-func insertLowestTime(arr *[3]time.Time, expire time.Time) {
-	if arr[0].IsZero() || expire.Before(arr[0]) {
-		arr[2] = arr[1]
-		arr[1] = arr[0]
-		arr[0] = expire
-	} else if arr[1].IsZero() || expire.Before(arr[1]) {
-		arr[2] = arr[1]
-		arr[1] = expire
-	} else if arr[2].IsZero() || expire.Before(arr[2]) {
-		arr[2] = expire
+	timers  sweeperTimerHeap
+	signal  chan struct{}
+	mu      sync.Mutex
+	running bool
+}
+
+// Adds timer that will call its fire function (in new goroutine) once it expires.
+func (s *interactionSweeper) schedule(client *BaseClient, timer *sweeperTimer) {
+	s.mu.Lock()
+	heap.Push(&s.timers, timer)
+	s.wakeLocked(client, timer)
+	s.mu.Unlock()
+}
+
+// Removes timer without firing it. Returns false when timer already fired (or was cancelled before).
+func (s *interactionSweeper) cancel(timer *sweeperTimer) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer.index < 0 {
+		return false
 	}
+
+	heap.Remove(&s.timers, timer.index)
+	return true
 }
 
-func (s *interactionSweeper) tryRun(client *BaseClient, expire time.Time) {
+// Moves timer to new expiry time. Returns false when timer already fired (or got cancelled).
+func (s *interactionSweeper) reset(client *BaseClient, timer *sweeperTimer, expire time.Time) bool {
 	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	oldShortest := s.lowestTimes[0]
+	if timer.index < 0 {
+		return false
+	}
 
-	insertLowestTime(&s.lowestTimes, expire)
+	timer.expire = expire
+	heap.Fix(&s.timers, timer.index)
+	s.wakeLocked(client, timer)
+	return true
+}
 
-	isNewShortest := oldShortest.IsZero() || expire.Before(oldShortest)
+// Returns whether timer expired (even if sweeper didn't fire it yet).
+func (s *interactionSweeper) expired(timer *sweeperTimer, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return timer.index < 0 || now.After(timer.expire)
+}
 
+func (s *interactionSweeper) size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+// Starts sweeper goroutine or interrupts its wait if timer became the earliest one.
+func (s *interactionSweeper) wakeLocked(client *BaseClient, timer *sweeperTimer) {
 	if !s.running {
 		s.running = true
-		s.mu.Unlock()
-		client.tracef("Starting interaction sweeper (initial timer: %s)", time.Until(expire).Round(time.Millisecond))
+		client.tracef("Starting interaction sweeper (initial timer: %s)", time.Until(timer.expire).Round(time.Millisecond))
 		go s.run(client)
 		return
 	}
-	s.mu.Unlock()
-
-	if isNewShortest {
-		client.tracef("Interaction Sweeper interrupted! New listener expires earlier (in %s).", time.Until(expire).Round(time.Millisecond))
 
+	if timer.index == 0 {
 		select {
 		case s.signal <- struct{}{}:
 		default:
 		}
-	} else {
-		client.tracef("Interaction Sweeper ignored new listener (expires in %s) as current lowest timer has lower time value", time.Until(expire).Round(time.Millisecond))
 	}
 }
 
 func (s *interactionSweeper) run(client *BaseClient) {
-	timer := time.NewTimer(time.Minute)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
 	defer timer.Stop()
 
 	for {
 		s.mu.Lock()
-		var shortest time.Duration = -1
-		lowestTimesCopy := s.lowestTimes
-		if !s.lowestTimes[0].IsZero() {
-			now := time.Now()
-			if now.After(s.lowestTimes[0]) || now.Equal(s.lowestTimes[0]) {
-				shortest = 0
-			} else {
-				shortest = s.lowestTimes[0].Sub(now)
-			}
+		if len(s.timers) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			client.tracef("Interaction sweeper stopped (no pending listeners).")
+			return
 		}
+		wait := time.Until(s.timers[0].expire)
 		s.mu.Unlock()
 
-		if !timer.Stop() {
+		if wait > 0 {
+			timer.Reset(wait)
 			select {
 			case <-timer.C:
-			default:
+			case <-s.signal:
+				timer.Stop()
+				continue
 			}
 		}
 
-		if shortest >= 0 {
-			timer.Reset(shortest)
+		now := time.Now()
+		var due []func()
 
-			var upcoming []time.Duration
-			now := time.Now()
-			for _, t := range lowestTimesCopy {
-				if !t.IsZero() {
-					upcoming = append(upcoming, t.Sub(now).Round(time.Millisecond))
-				}
-			}
+		s.mu.Lock()
+		for len(s.timers) != 0 && !s.timers[0].expire.After(now) {
+			due = append(due, heap.Pop(&s.timers).(*sweeperTimer).fire)
+		}
+		s.mu.Unlock()
 
-			client.tracef("Interaction Sweeper timer set to %s. Run cleanup in: %v", shortest.Round(time.Millisecond), upcoming)
+		if len(due) != 0 {
+			client.tracef("Interaction sweeper expired %d listener(s).", len(due))
 		}
 
-		select {
-		case <-timer.C:
-		case <-s.signal:
-			client.tracef("Interaction Sweeper timer reset to adapt to new shorter listener timeouts.")
+		for _, fire := range due {
+			go fire()
+		}
+	}
+}
+
+// Number of pending dynamic listeners (see BaseClient.ListenerCounts).
+type ListenerCounts struct {
+	Components int // Custom IDs awaited with AwaitComponent (and helpers built on it).
+	Modals     int // Custom IDs awaited with AwaitModal.
+	Timers     int // Pending timeouts - one per Await call, no matter how many custom IDs it covers.
+}
+
+// Returns number of dynamic listeners that are still waiting for interactions.
+func (client *BaseClient) ListenerCounts() ListenerCounts {
+	return ListenerCounts{
+		Components: client.queuedComponents.Size(),
+		Modals:     client.queuedModals.Size(),
+		Timers:     client.sweeper.size(),
+	}
+}
+
+// Extends (or shortens) timeout of dynamic component listener, counting from now.
+// It affects all custom IDs registered together with customID. Returns false when there's no such (active) listener.
+func (client *BaseClient) ExtendComponentListener(customID string, timeout time.Duration) bool {
+	entry, ok := client.queuedComponents.Get(customID)
+	if !ok {
+		return false
+	}
+	return client.sweeper.reset(client, entry.timer, time.Now().Add(timeout))
+}
+
+// Extends (or shortens) timeout of dynamic modal listener, counting from now. See [BaseClient.ExtendComponentListener].
+func (client *BaseClient) ExtendModalListener(customID string, timeout time.Duration) bool {
+	entry, ok := client.queuedModals.Get(customID)
+	if !ok {
+		return false
+	}
+	return client.sweeper.reset(client, entry.timer, time.Now().Add(timeout))
+}
+
+// Removes dynamic component listeners (all custom IDs registered together with any of provided ones) without running their callbacks.
+func (client *BaseClient) cancelComponentListeners(customIDs ...string) {
+	client.queuedComponents.mu.Lock()
+	defer client.queuedComponents.mu.Unlock()
+
+	for _, id := range customIDs {
+		entry, ok := client.queuedComponents.cache[id]
+		if !ok {
 			continue
 		}
 
-		now := time.Now()
-		var newLowest [3]time.Time
-
-		client.queuedComponents.Sweep(func(key string, value *queuedComponent) bool {
-			if now.After(value.Expire) {
-				if value.OnTimeout != nil {
-					go value.OnTimeout()
-				}
-				return true
-			}
-			insertLowestTime(&newLowest, value.Expire)
-			return false
-		})
-
-		client.queuedModals.Sweep(func(key string, value *queuedModal) bool {
-			if now.After(value.Expire) {
-				if value.OnTimeout != nil {
-					go value.OnTimeout()
-				}
-				return true
+		client.sweeper.cancel(entry.timer)
+		for _, key := range entry.customIDs {
+			if client.queuedComponents.cache[key] == entry {
+				delete(client.queuedComponents.cache, key)
 			}
-			insertLowestTime(&newLowest, value.Expire)
-			return false
-		})
+		}
+	}
+}
 
-		qSize := client.queuedComponents.Size()
-		mSize := client.queuedModals.Size()
+// Removes dynamic modal listeners without running their callbacks. See cancelComponentListeners.
+func (client *BaseClient) cancelModalListeners(customIDs ...string) {
+	client.queuedModals.mu.Lock()
+	defer client.queuedModals.mu.Unlock()
 
-		s.mu.Lock()
-		s.lowestTimes = newLowest
-		if qSize == 0 && mSize == 0 {
-			s.running = false
-			s.mu.Unlock()
-			return
+	for _, id := range customIDs {
+		entry, ok := client.queuedModals.cache[id]
+		if !ok {
+			continue
+		}
+
+		client.sweeper.cancel(entry.timer)
+		for _, key := range entry.customIDs {
+			if client.queuedModals.cache[key] == entry {
+				delete(client.queuedModals.cache, key)
+			}
 		}
-		s.mu.Unlock()
 	}
 }
@@ -0,0 +1,133 @@
+package tempest
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestInteractionSweeper(t *testing.T) {
+	client := &BaseClient{sweeper: interactionSweeper{signal: make(chan struct{}, 1)}}
+	fired := make(chan string, 3)
+
+	timer := func(name string, after time.Duration) *sweeperTimer {
+		timer := &sweeperTimer{expire: time.Now().Add(after), fire: func() { fired <- name }}
+		client.sweeper.schedule(client, timer)
+		return timer
+	}
+
+	late := timer("late", time.Hour)
+	cancelled := timer("cancelled", 20*time.Millisecond)
+	timer("first", 40*time.Millisecond)
+
+	if !client.sweeper.cancel(cancelled) || client.sweeper.cancel(cancelled) {
+		t.Fatal("expected timer to be cancelled exactly once")
+	}
+
+	if !client.sweeper.reset(client, late, time.Now().Add(60*time.Millisecond)) {
+		t.Fatal("expected pending timer to be extended")
+	}
+
+	for _, expected := range []string{"first", "late"} {
+		select {
+		case name := <-fired:
+			if name != expected {
+				t.Fatalf("expected %q timer to fire, got %q", expected, name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q timer", expected)
+		}
+	}
+
+	if client.sweeper.size() != 0 || client.sweeper.reset(client, late, time.Now()) {
+		t.Error("expected sweeper to be empty after all timers fired")
+	}
+}
+
+// Copy of previous sweeper strategy (3 lowest expiry times + full scan on each wake-up), kept for comparison.
+func legacyExpireAll(expires []time.Time) {
+	queued := make(map[int]time.Time, len(expires))
+	var lowest [3]time.Time
+	for i, expire := range expires {
+		queued[i] = expire
+		insertLegacyLowestTime(&lowest, expire)
+	}
+
+	for len(queued) != 0 {
+		now := lowest[0]
+		var next [3]time.Time
+		for key, expire := range queued {
+			if now.After(expire) || now.Equal(expire) {
+				delete(queued, key)
+				continue
+			}
+			insertLegacyLowestTime(&next, expire)
+		}
+		lowest = next
+	}
+}
+
+func insertLegacyLowestTime(arr *[3]time.Time, expire time.Time) {
+	if arr[0].IsZero() || expire.Before(arr[0]) {
+		arr[2], arr[1], arr[0] = arr[1], arr[0], expire
+	} else if arr[1].IsZero() || expire.Before(arr[1]) {
+		arr[2], arr[1] = arr[1], expire
+	} else if arr[2].IsZero() || expire.Before(arr[2]) {
+		arr[2] = expire
+	}
+}
+
+func heapExpireAll(expires []time.Time) {
+	timers := make(sweeperTimerHeap, 0, len(expires))
+	for _, expire := range expires {
+		heap.Push(&timers, &sweeperTimer{expire: expire})
+	}
+
+	for len(timers) != 0 {
+		now := timers[0].expire
+		for len(timers) != 0 && !timers[0].expire.After(now) {
+			heap.Pop(&timers)
+		}
+	}
+}
+
+func BenchmarkSweeperExpireAll(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		start := time.Now()
+		expires := make([]time.Time, n)
+		for i := range expires {
+			expires[i] = start.Add(time.Duration(rand.IntN(15*60*1000)) * time.Millisecond)
+		}
+
+		b.Run(fmt.Sprintf("legacy/%d", n), func(b *testing.B) {
+			for range b.N {
+				legacyExpireAll(expires)
+			}
+		})
+
+		b.Run(fmt.Sprintf("heap/%d", n), func(b *testing.B) {
+			for range b.N {
+				heapExpireAll(expires)
+			}
+		})
+	}
+}
+
+func BenchmarkAwaitComponent(b *testing.B) {
+	client := &BaseClient{
+		staticComponents: NewSharedMap[string, func(ComponentInteraction)](),
+		queuedComponents: NewSharedMap[string, *queuedComponent](),
+		sweeper:          interactionSweeper{signal: make(chan struct{}, 1)},
+	}
+
+	b.ResetTimer()
+	for i := range b.N {
+		id := "bench:" + fmt.Sprint(i)
+		if err := client.AwaitComponent([]string{id}, time.Hour, func(*ComponentInteraction) bool { return true }, nil); err != nil {
+			b.Fatal(err)
+		}
+		client.cancelComponentListeners(id)
+	}
+}
@@ -0,0 +1,246 @@
+package tempest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Single run of command, component or modal handler, as seen by middleware.
+// Exactly one of Command, Component or Modal fields is set (matching Kind).
+type Invocation struct {
+	Interaction *Interaction          // Always provided. Shared by all typed interactions below.
+	Command     *CommandInteraction   // Provided for slash & context menu commands.
+	Component   *ComponentInteraction // Provided for components.
+	Modal       *ModalInteraction     // Provided for modals.
+	Meta        Command               // Definition of invoked (sub) command. Empty for components & modals.
+	Name        string                // Registry key of command - full path (like "config@logging@channel") or type prefixed name of context menu command (like "user:Report"). Custom ID for components & modals.
+	Kind        InteractionType
+}
+
+// Final handler or any layer of middleware chain. Returned error is passed up the chain,
+// so middleware can inspect (or swallow) errors of deeper layers.
+type Handler func(inv *Invocation) error
+
+// Wraps handler with extra logic - middleware can run code before/after calling next handler or skip it entirely (by not calling next).
+//
+// Example:
+//
+//	func Logger(next tempest.Handler) tempest.Handler {
+//		return func(inv *tempest.Invocation) error {
+//			start := time.Now()
+//			err := next(inv)
+//			log.Printf("%s took %v (err = %v)", inv.Name, time.Since(start), err)
+//			return err
+//		}
+//	}
+type Middleware func(next Handler) Handler
+
+// Adds middleware that wraps every slash command (including subcommands).
+// Global middleware runs first (in order of registration), then middleware of root command, subcommand group and subcommand (see Command.Middlewares).
+//
+// Auto complete interactions skip middleware as they need to respond as fast as possible.
+// It's safe to call Use at any time, but interactions that are already being handled keep using old chain.
+func (client *BaseClient) Use(middlewares ...Middleware) {
+	client.commandMiddlewares.add(middlewares...)
+}
+
+// Adds middleware that wraps every component handler - static, dynamic (awaited) and global one.
+func (client *BaseClient) UseComponents(middlewares ...Middleware) {
+	client.componentMiddlewares.add(middlewares...)
+}
+
+// Adds middleware that wraps every modal handler - static, dynamic (awaited) and global one.
+func (client *BaseClient) UseModals(middlewares ...Middleware) {
+	client.modalMiddlewares.add(middlewares...)
+}
+
+// Copy-on-write list of middleware. Readers never lock, so it can grow while client handles interactions.
+type middlewareList struct {
+	list atomic.Pointer[[]Middleware]
+	mu   sync.Mutex
+}
+
+func (l *middlewareList) add(middlewares ...Middleware) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.load()
+	next := make([]Middleware, 0, len(current)+len(middlewares))
+	next = append(append(next, current...), middlewares...)
+	l.list.Store(&next)
+}
+
+func (l *middlewareList) load() []Middleware {
+	if list := l.list.Load(); list != nil {
+		return *list
+	}
+	return nil
+}
+
+// Builds handler wrapped in all middleware. First middleware becomes the outermost layer.
+func chainMiddlewares(handler Handler, groups ...[]Middleware) Handler {
+	for i := len(groups) - 1; i >= 0; i-- {
+		for j := len(groups[i]) - 1; j >= 0; j-- {
+			handler = groups[i][j](handler)
+		}
+	}
+	return handler
+}
+
+// Runs command handler wrapped in global and per (sub) command middleware. Errors and panics are passed to client's error handler.
+// Auto defer timer (if enabled) runs only while handler does, so it never answers interactions that handler deliberately left alone.
+func (client *BaseClient) runCommand(itx *CommandInteraction, command Command) {
+	client.autoDeferCommand(itx.Interaction, command.AutoDefer)
+	defer client.disarmAutoDefer(itx.Interaction)
+
+	key := commandRegistryKey(itx.Data.Type, itx.Data.Name)
+	groups := make([][]Middleware, 0, 4)
+	groups = append(groups, client.commandMiddlewares.load())
+
+	// Collect middleware of parent command & group, for subcommands.
+	if parts := strings.Split(key, "@"); len(parts) > 1 && isSlashCommandKey(key) {
+		for i := 1; i < len(parts); i++ {
+			if parent, ok := client.commands.Get(strings.Join(parts[:i], "@")); ok {
+				groups = append(groups, parent.Middlewares)
+			}
+		}
+	}
+	groups = append(groups, command.Middlewares)
+	groups = append(groups, []Middleware{client.limitsMiddleware})
+
+	handler := chainMiddlewares(func(inv *Invocation) error {
+		switch {
+		case inv.Meta.UserCommandHandler != nil:
+			user, member, ok := inv.Command.TargetUser()
+			if !ok {
+				return errors.New("user command interaction has no resolved target user")
+			}
+			return inv.Meta.UserCommandHandler(inv.Command, user, member)
+		case inv.Meta.MessageCommandHandler != nil:
+			message, ok := inv.Command.TargetMessage()
+			if !ok {
+				return errors.New("message command interaction has no resolved target message")
+			}
+			return inv.Meta.MessageCommandHandler(inv.Command, message)
+		case inv.Meta.SlashCommandHandlerE != nil:
+			return inv.Meta.SlashCommandHandlerE(inv.Command)
+		}
+
+		inv.Meta.SlashCommandHandler(inv.Command)
+		return nil
+	}, groups...)
+
+	inv := &Invocation{
+		Interaction: itx.Interaction,
+		Command:     itx,
+		Meta:        command,
+		Name:        key,
+		Kind:        APPLICATION_COMMAND_INTERACTION_TYPE,
+	}
+
+	start := time.Now()
+	err := invokeHandler(handler, inv)
+
+	if client.trace {
+		client.tracef("Command %s execution took %v", command.Name, time.Since(start))
+	}
+
+	if err != nil {
+		client.handleError(itx.Interaction, err)
+	}
+}
+
+// Runs component handler wrapped in component middleware.
+func (client *BaseClient) runComponent(itx *ComponentInteraction, fn func(itx *ComponentInteraction)) {
+	client.autoDeferUpdate(itx.Interaction)
+	defer client.disarmAutoDefer(itx.Interaction)
+
+	handler := chainMiddlewares(func(inv *Invocation) error {
+		fn(inv.Component)
+		return nil
+	}, client.componentMiddlewares.load())
+
+	err := invokeHandler(handler, &Invocation{
+		Interaction: itx.Interaction,
+		Component:   itx,
+		Name:        itx.Data.CustomID,
+		Kind:        MESSAGE_COMPONENT_INTERACTION_TYPE,
+	})
+	if err != nil {
+		client.handleError(itx.Interaction, err)
+	}
+}
+
+// Runs modal handler wrapped in modal middleware.
+func (client *BaseClient) runModal(itx *ModalInteraction, fn func(itx *ModalInteraction)) {
+	client.autoDeferUpdate(itx.Interaction)
+	defer client.disarmAutoDefer(itx.Interaction)
+
+	handler := chainMiddlewares(func(inv *Invocation) error {
+		fn(inv.Modal)
+		return nil
+	}, client.modalMiddlewares.load())
+
+	err := invokeHandler(handler, &Invocation{
+		Interaction: itx.Interaction,
+		Modal:       itx,
+		Name:        itx.Data.CustomID,
+		Kind:        MODAL_SUBMIT_INTERACTION_TYPE,
+	})
+	if err != nil {
+		client.handleError(itx.Interaction, err)
+	}
+}
+
+// Turns legacy pre & post command hooks into middleware.
+func hooksMiddleware(pre func(cmd Command, itx *CommandInteraction) bool, post func(cmd Command, itx *CommandInteraction)) Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) error {
+			if pre != nil && !pre(inv.Meta, inv.Command) {
+				return nil
+			}
+
+			err := next(inv)
+
+			if post != nil {
+				post(inv.Meta, inv.Command)
+			}
+			return err
+		}
+	}
+}
+
+// Stores value in interaction's store, so it can be shared between middleware & handlers during single invocation.
+func (itx *Interaction) SetValue(key string, value any) {
+	itx.valuesMu.Lock()
+	defer itx.valuesMu.Unlock()
+
+	if itx.values == nil {
+		itx.values = make(map[string]any, 1)
+	}
+	itx.values[key] = value
+}
+
+// Returns value from interaction's store (see [Interaction.SetValue]).
+func (itx *Interaction) Value(key string) (any, bool) {
+	itx.valuesMu.Lock()
+	defer itx.valuesMu.Unlock()
+
+	value, ok := itx.values[key]
+	return value, ok
+}
+
+// Returns value from interaction's store, if it exists and has matching type.
+func InteractionValue[T any](itx *Interaction, key string) (T, bool) {
+	raw, ok := itx.Value(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	value, ok := raw.(T)
+	return value, ok
+}
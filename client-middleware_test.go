@@ -0,0 +1,53 @@
+package tempest
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestUseWhileHandlingInteractions(t *testing.T) {
+	client := &BaseClient{}
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(inv *Invocation) error {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return next(inv)
+			}
+		}
+	}
+
+	noop := func(next Handler) Handler { return next }
+	newInteraction := func() *ComponentInteraction {
+		return &ComponentInteraction{Interaction: &Interaction{Type: MESSAGE_COMPONENT_INTERACTION_TYPE}}
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.runComponent(newInteraction(), func(*ComponentInteraction) {})
+		}()
+		go func() {
+			defer wg.Done()
+			client.UseComponents(noop)
+		}()
+	}
+	wg.Wait()
+
+	if n := len(client.componentMiddlewares.load()); n != 20 {
+		t.Fatalf("expected 20 middleware after concurrent registration, got %d", n)
+	}
+
+	client.UseComponents(record("first"), record("second"))
+	client.runComponent(newInteraction(), func(*ComponentInteraction) {})
+	if !slices.Equal(order, []string{"first", "second"}) {
+		t.Errorf("expected middleware to run in registration order, got %v", order)
+	}
+}
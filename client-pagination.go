@@ -0,0 +1,376 @@
+package tempest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Controls how paginated iterators walk through Discord's list endpoints.
+//
+// Use Before to walk towards older items (lower snowflakes) and After to walk towards newer items (higher snowflakes).
+// When neither is set, each iterator uses the natural direction of its endpoint (documented on the method).
+type PageOptions struct {
+	Before   Snowflake // Start iterating from items older than this ID.
+	After    Snowflake // Start iterating from items newer than this ID.
+	MaxItems uint32    // Stop after yielding that many items. Set to 0 to walk through all pages.
+	PageSize uint16    // Number of items requested per API call. By default it uses the highest value allowed by endpoint.
+}
+
+// https://docs.discord.com/developers/resources/entitlement#list-entitlements-query-string-params
+type EntitlementFilter struct {
+	SkuIDs []Snowflake
+	PageOptions
+	UserID         Snowflake
+	GuildID        Snowflake
+	ExcludeEnded   bool
+	ExcludeDeleted bool
+}
+
+// https://docs.discord.com/developers/resources/audit-log#get-guild-audit-log-query-string-params
+type AuditLogFilter struct {
+	PageOptions
+	UserID     Snowflake     // Only return entries for actions made by this user.
+	ActionType AuditLogEvent // Only return entries of this action type (0 means all types).
+}
+
+// https://docs.discord.com/developers/resources/channel#list-public-archived-threads-query-string-params
+type ArchivedThreadFilter struct {
+	Before   time.Time // Start iterating from threads archived before this timestamp.
+	MaxItems uint32    // Stop after yielding that many items. Set to 0 to walk through all pages.
+	PageSize uint16    // Number of threads requested per API call. By default: 100.
+	Private  bool      // Whether to list private (instead public) archived threads. Requires MANAGE_THREADS permission.
+}
+
+// Walks through every entitlement of this application that matches filter.
+// Without Before/After cursor it yields oldest entitlements first.
+//
+// https://docs.discord.com/developers/resources/entitlement#list-entitlements
+func (client *BaseClient) Entitlements(ctx context.Context, filter EntitlementFilter) iter.Seq2[Entitlement, error] {
+	query := url.Values{}
+	if filter.UserID != 0 {
+		query.Set("user_id", filter.UserID.String())
+	}
+
+	if filter.GuildID != 0 {
+		query.Set("guild_id", filter.GuildID.String())
+	}
+
+	if len(filter.SkuIDs) != 0 {
+		ids := make([]string, len(filter.SkuIDs))
+		for i, id := range filter.SkuIDs {
+			ids[i] = id.String()
+		}
+		query.Set("sku_ids", strings.Join(ids, ","))
+	}
+
+	if filter.ExcludeEnded {
+		query.Set("exclude_ended", "true")
+	}
+
+	if filter.ExcludeDeleted {
+		query.Set("exclude_deleted", "true")
+	}
+
+	return paginateSnowflakes(ctx, client.Rest, "/applications/"+client.ApplicationID.String()+"/entitlements", query, filter.PageOptions, 100, false,
+		func(item Entitlement) Snowflake { return item.ID },
+		decodePage[Entitlement],
+	)
+}
+
+// Walks through all members of the guild, ordered by user ID (ascending).
+// Only After cursor is supported by Discord. Requires GUILD_MEMBERS privileged intent to be enabled for your app.
+//
+// https://docs.discord.com/developers/resources/guild#list-guild-members
+func (client *BaseClient) GuildMembers(ctx context.Context, guildID Snowflake, opt PageOptions) iter.Seq2[Member, error] {
+	if opt.Before != 0 {
+		return failedSeq[Member](errors.New("guild members endpoint does not support \"before\" cursor"))
+	}
+
+	return paginateSnowflakes(ctx, client.Rest, "/guilds/"+guildID.String()+"/members", url.Values{}, opt, 1000, false,
+		func(item Member) Snowflake {
+			if item.User == nil {
+				return 0
+			}
+			return item.User.ID
+		},
+		func(raw []byte) ([]Member, error) {
+			members, err := decodePage[Member](raw)
+			for i := range members {
+				members[i].GuildID = guildID
+			}
+			return members, err
+		},
+	)
+}
+
+// Walks through all bans of the guild. Without Before/After cursor it yields bans ordered by user ID (ascending).
+//
+// https://docs.discord.com/developers/resources/guild#get-guild-bans
+func (client *BaseClient) GuildBans(ctx context.Context, guildID Snowflake, opt PageOptions) iter.Seq2[Ban, error] {
+	return paginateSnowflakes(ctx, client.Rest, "/guilds/"+guildID.String()+"/bans", url.Values{}, opt, 1000, false,
+		func(item Ban) Snowflake { return item.User.ID },
+		decodePage[Ban],
+	)
+}
+
+// Walks through messages of the channel. Without Before/After cursor it starts from the newest message and goes back in time.
+// Messages within a single page are yielded in order returned by Discord (newest first).
+//
+// https://docs.discord.com/developers/resources/message#get-channel-messages
+func (client *BaseClient) ChannelMessages(ctx context.Context, channelID Snowflake, opt PageOptions) iter.Seq2[Message, error] {
+	return paginateSnowflakes(ctx, client.Rest, "/channels/"+channelID.String()+"/messages", url.Values{}, opt, 100, true,
+		func(item Message) Snowflake { return item.ID },
+		decodePage[Message],
+	)
+}
+
+// Walks through users that reacted with provided emoji, ordered by user ID (ascending).
+// Emoji must be either unicode character or custom emoji in "name:id" format. Only After cursor is supported by Discord.
+//
+// https://docs.discord.com/developers/resources/message#get-reactions
+func (client *BaseClient) ReactionUsers(ctx context.Context, channelID Snowflake, messageID Snowflake, emoji string, opt PageOptions) iter.Seq2[User, error] {
+	if opt.Before != 0 {
+		return failedSeq[User](errors.New("reactions endpoint does not support \"before\" cursor"))
+	}
+
+	return paginateSnowflakes(ctx, client.Rest, "/channels/"+channelID.String()+"/messages/"+messageID.String()+"/reactions/"+url.PathEscape(emoji), url.Values{}, opt, 100, false,
+		func(item User) Snowflake { return item.ID },
+		decodePage[User],
+	)
+}
+
+// Walks through entries of guild's audit log. Without Before/After cursor it starts from the newest entry and goes back in time.
+// Requires VIEW_AUDIT_LOG permission.
+//
+// https://docs.discord.com/developers/resources/audit-log#get-guild-audit-log
+func (client *BaseClient) AuditLogEntries(ctx context.Context, guildID Snowflake, filter AuditLogFilter) iter.Seq2[AuditLogEntry, error] {
+	query := url.Values{}
+	if filter.UserID != 0 {
+		query.Set("user_id", filter.UserID.String())
+	}
+
+	if filter.ActionType != 0 {
+		query.Set("action_type", strconv.FormatUint(uint64(filter.ActionType), 10))
+	}
+
+	return paginateSnowflakes(ctx, client.Rest, "/guilds/"+guildID.String()+"/audit-logs", query, filter.PageOptions, 100, true,
+		func(item AuditLogEntry) Snowflake { return item.ID },
+		func(raw []byte) ([]AuditLogEntry, error) {
+			var res struct {
+				Entries []AuditLogEntry `json:"audit_log_entries"`
+			}
+
+			if err := json.Unmarshal(raw, &res); err != nil {
+				return nil, errors.New("failed to parse received data from discord")
+			}
+			return res.Entries, nil
+		},
+	)
+}
+
+// Walks through archived threads of the channel, starting from the most recently archived ones.
+// Unlike other list endpoints, Discord uses archive timestamp (instead snowflake) as cursor here.
+//
+// https://docs.discord.com/developers/resources/channel#list-public-archived-threads
+//
+// https://docs.discord.com/developers/resources/channel#list-private-archived-threads
+func (client *BaseClient) ArchivedThreads(ctx context.Context, channelID Snowflake, filter ArchivedThreadFilter) iter.Seq2[ThreadChannel, error] {
+	route := "/channels/" + channelID.String() + "/threads/archived/public"
+	if filter.Private {
+		route = "/channels/" + channelID.String() + "/threads/archived/private"
+	}
+
+	pageSize := filter.PageSize
+	if pageSize == 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	return func(yieldFn func(ThreadChannel, error) bool) {
+		var yielded uint32
+		cursor := filter.Before
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yieldFn(ThreadChannel{}, err)
+				return
+			}
+
+			limit := uint32(pageSize)
+			if filter.MaxItems != 0 && filter.MaxItems-yielded < limit {
+				limit = filter.MaxItems - yielded
+			}
+
+			query := url.Values{}
+			query.Set("limit", strconv.FormatUint(uint64(limit), 10))
+			if !cursor.IsZero() {
+				query.Set("before", cursor.UTC().Format(time.RFC3339Nano))
+			}
+
+			raw, err := client.Rest.RequestWithOptions(http.MethodGet, route+"?"+query.Encode(), nil, RequestOptions{Context: ctx})
+			if err != nil {
+				yieldFn(ThreadChannel{}, err)
+				return
+			}
+
+			var res struct {
+				Threads []ThreadChannel `json:"threads"`
+				HasMore bool            `json:"has_more"`
+			}
+
+			if err := json.Unmarshal(raw, &res); err != nil {
+				yieldFn(ThreadChannel{}, errors.New("failed to parse received data from discord"))
+				return
+			}
+
+			previous := cursor
+			for _, thread := range res.Threads {
+				archivedAt := thread.ThreadMetadata.ArchiveTimestamp
+				if archivedAt != nil && (cursor.IsZero() || archivedAt.Before(cursor)) {
+					cursor = *archivedAt
+				}
+
+				if !yieldFn(thread, nil) {
+					return
+				}
+
+				yielded++
+				if filter.MaxItems != 0 && yielded >= filter.MaxItems {
+					return
+				}
+			}
+
+			if !res.HasMore || len(res.Threads) == 0 {
+				return
+			}
+
+			if cursor.Equal(previous) {
+				yieldFn(ThreadChannel{}, errPaginationStalled)
+				return
+			}
+		}
+	}
+}
+
+// Returned by iterators when page gives no usable cursor - requesting next page would return the same items again.
+var errPaginationStalled = errors.New("pagination cursor did not advance - no item on the page can be used as cursor")
+
+func decodePage[T any](raw []byte) ([]T, error) {
+	res := make([]T, 0)
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, errors.New("failed to parse received data from discord")
+	}
+	return res, nil
+}
+
+func failedSeq[T any](err error) iter.Seq2[T, error] {
+	return func(yieldFn func(T, error) bool) {
+		var zero T
+		yieldFn(zero, err)
+	}
+}
+
+// Generic walker for list endpoints that use snowflake based "before" & "after" cursors.
+// It never trusts order of received items - next cursor is always the lowest (before) or highest (after) ID found on the page.
+func paginateSnowflakes[T any](
+	ctx context.Context,
+	rest *Rest,
+	route string,
+	query url.Values,
+	opt PageOptions,
+	maxPageSize uint16,
+	defaultBefore bool,
+	idOf func(item T) Snowflake,
+	decode func(raw []byte) ([]T, error),
+) iter.Seq2[T, error] {
+	pageSize := opt.PageSize
+	if pageSize == 0 || pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return func(yieldFn func(T, error) bool) {
+		var zero T
+		var yielded uint32
+
+		query := maps.Clone(query)
+		before := defaultBefore
+		cursor := opt.After
+		if opt.Before != 0 {
+			before, cursor = true, opt.Before
+		} else if opt.After != 0 {
+			before = false
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yieldFn(zero, err)
+				return
+			}
+
+			limit := uint32(pageSize)
+			if opt.MaxItems != 0 && opt.MaxItems-yielded < limit {
+				limit = opt.MaxItems - yielded
+			}
+
+			query.Set("limit", strconv.FormatUint(uint64(limit), 10))
+			if cursor != 0 {
+				if before {
+					query.Set("before", cursor.String())
+				} else {
+					query.Set("after", cursor.String())
+				}
+			}
+
+			raw, err := rest.RequestWithOptions(http.MethodGet, route+"?"+query.Encode(), nil, RequestOptions{Context: ctx})
+			if err != nil {
+				yieldFn(zero, err)
+				return
+			}
+
+			items, err := decode(raw)
+			if err != nil {
+				yieldFn(zero, err)
+				return
+			}
+
+			previous := cursor
+			for _, item := range items {
+				id := idOf(item)
+				if id == 0 {
+					continue
+				}
+
+				if before && (cursor == 0 || id < cursor) || !before && id > cursor {
+					cursor = id
+				}
+			}
+
+			for _, item := range items {
+				if !yieldFn(item, nil) {
+					return
+				}
+
+				yielded++
+				if opt.MaxItems != 0 && yielded >= opt.MaxItems {
+					return
+				}
+			}
+
+			if uint32(len(items)) < limit {
+				return
+			}
+
+			if cursor == previous {
+				yieldFn(zero, errPaginationStalled)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package tempest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type paginationTestKey struct{}
+
+// Serves users with provided IDs the way Discord does it, but returns each page in reversed order.
+func newPaginationRest(t *testing.T, ids []Snowflake, queries *[]string) *Rest {
+	return newStubRest(func(req *http.Request) (*http.Response, error) {
+		if req.Context().Value(paginationTestKey{}) == nil {
+			t.Error("expected request to carry iterator's context")
+		}
+
+		query := req.URL.Query()
+		*queries = append(*queries, query.Encode())
+
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		page := make([]User, 0, limit)
+		if before, _ := StringToSnowflake(query.Get("before")); before != 0 || query.Get("after") == "" {
+			for i := len(ids) - 1; i >= 0 && len(page) < limit; i-- {
+				if before == 0 || ids[i] < before {
+					page = append(page, User{ID: ids[i]})
+				}
+			}
+		} else {
+			after, _ := StringToSnowflake(query.Get("after"))
+			for i := 0; i < len(ids) && len(page) < limit; i++ {
+				if ids[i] > after {
+					page = append(page, User{ID: ids[i]})
+				}
+			}
+			slices.Reverse(page)
+		}
+
+		raw, _ := json.Marshal(page)
+		return stubResponse(http.StatusOK, string(raw)), nil
+	})
+}
+
+func TestPaginateSnowflakes(t *testing.T) {
+	ctx := context.WithValue(context.Background(), paginationTestKey{}, true)
+	ids := []Snowflake{11, 12, 13, 14, 15}
+
+	cases := []struct {
+		name          string
+		opt           PageOptions
+		defaultBefore bool
+		expected      []Snowflake
+		queries       []string
+	}{
+		{
+			name:     "after cursor stops on short page",
+			opt:      PageOptions{After: 10, PageSize: 2},
+			expected: []Snowflake{12, 11, 14, 13, 15},
+			queries:  []string{"after=10&limit=2", "after=12&limit=2", "after=14&limit=2"},
+		},
+		{
+			name:          "default before direction",
+			opt:           PageOptions{PageSize: 2},
+			defaultBefore: true,
+			expected:      []Snowflake{15, 14, 13, 12, 11},
+			queries:       []string{"limit=2", "before=14&limit=2", "before=12&limit=2"},
+		},
+		{
+			name:     "before cursor with max items capping limit",
+			opt:      PageOptions{Before: 15, PageSize: 2, MaxItems: 3},
+			expected: []Snowflake{14, 13, 12},
+			queries:  []string{"before=15&limit=2", "before=13&limit=1"},
+		},
+	}
+
+	for _, tc := range cases {
+		var queries []string
+		rest := newPaginationRest(t, ids, &queries)
+
+		var got []Snowflake
+		for user, err := range paginateSnowflakes(ctx, rest, "/users", url.Values{}, tc.opt, 100, tc.defaultBefore, func(u User) Snowflake { return u.ID }, decodePage[User]) {
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			got = append(got, user.ID)
+		}
+
+		if !slices.Equal(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+
+		if !slices.Equal(queries, tc.queries) {
+			t.Errorf("%s: expected queries %v, got %v", tc.name, tc.queries, queries)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	var queries []string
+	rest := newPaginationRest(t, ids, &queries)
+	for _, err := range paginateSnowflakes(cancelled, rest, "/users", url.Values{}, PageOptions{}, 100, false, func(u User) Snowflake { return u.ID }, decodePage[User]) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context error, got %v", err)
+		}
+	}
+
+	if len(queries) != 0 {
+		t.Errorf("expected no requests after context got cancelled, got %v", queries)
+	}
+}
+
+func TestPaginationStopsWhenCursorStalls(t *testing.T) {
+	ctx := context.WithValue(context.Background(), paginationTestKey{}, true)
+
+	requests := 0
+	rest := newStubRest(func(*http.Request) (*http.Response, error) {
+		requests++
+		return stubResponse(http.StatusOK, `[{"joined_at":"2024-01-01T00:00:00Z"},{"joined_at":"2024-01-01T00:00:00Z"}]`), nil
+	})
+
+	var yielded int
+	var lastErr error
+	for _, err := range paginateSnowflakes(ctx, rest, "/guilds/1/members", url.Values{}, PageOptions{PageSize: 2}, 1000, false, func(m Member) Snowflake {
+		if m.User == nil {
+			return 0
+		}
+		return m.User.ID
+	}, decodePage[Member]) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		yielded++
+	}
+
+	if !errors.Is(lastErr, errPaginationStalled) || yielded != 2 || requests != 1 {
+		t.Errorf("expected single page followed by stall error, got %d items, %d requests, err = %v", yielded, requests, lastErr)
+	}
+}
+
+func TestArchivedThreads(t *testing.T) {
+	var queries []string
+	client := &BaseClient{Rest: newStubRest(func(req *http.Request) (*http.Response, error) {
+		queries = append(queries, req.URL.Query().Encode())
+		return stubResponse(http.StatusOK, `{"threads":[{"id":"1"},{"id":"2"}],"has_more":true}`), nil
+	})}
+
+	var yielded int
+	var lastErr error
+	for _, err := range client.ArchivedThreads(context.Background(), 5, ArchivedThreadFilter{PageSize: 2}) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		yielded++
+	}
+
+	if !errors.Is(lastErr, errPaginationStalled) || yielded != 2 || len(queries) != 1 {
+		t.Errorf("expected threads without archive timestamp to stop iteration, got %d items, queries %v, err = %v", yielded, queries, lastErr)
+	}
+
+	queries = nil
+	for _, err := range client.ArchivedThreads(context.Background(), 5, ArchivedThreadFilter{MaxItems: 1}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !slices.Equal(queries, []string{"limit=1"}) {
+		t.Errorf("expected MaxItems to cap limit, got queries %v", queries)
+	}
+}
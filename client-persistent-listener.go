@@ -0,0 +1,348 @@
+package tempest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Serializable record of dynamic listener created with [BaseClient.AwaitPersistentComponent] or [BaseClient.AwaitPersistentModal].
+type PersistentListener struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"` // Name of handler kind registered with RegisterListenerKind.
+	CustomIDs []string        `json:"custom_ids"`
+	State     json.RawMessage `json:"state,omitempty"`
+	Expire    time.Time       `json:"expire"`
+	Modal     bool            `json:"modal,omitempty"` // Whether listener awaits modals instead of components.
+}
+
+// Storage for persistent listeners. Implementations must be safe for concurrent use.
+type ListenerStore interface {
+	Save(listener PersistentListener) error
+	Delete(id string) error
+	Load() ([]PersistentListener, error)
+}
+
+// Named set of handlers for persistent listeners. Handlers receive state that was passed when listener was created.
+// Just like with AwaitComponent, returning true from OnComponent/OnModal finishes listener.
+type ListenerKind[T any] struct {
+	OnComponent func(itx *ComponentInteraction, state T) bool
+	OnModal     func(itx *ModalInteraction, state T) bool
+	OnTimeout   func(state T) // Runs when listener times out, also for listeners that expired while app was offline (after RestoreListeners).
+}
+
+// Type erased version of ListenerKind, stored in client.
+type listenerKind struct {
+	onComponent func(itx *ComponentInteraction, state json.RawMessage) bool
+	onModal     func(itx *ModalInteraction, state json.RawMessage) bool
+	onTimeout   func(state json.RawMessage)
+}
+
+// Registers named handlers for persistent listeners. Kinds need to be registered (under the same names) before calling RestoreListeners.
+func RegisterListenerKind[T any](client *BaseClient, name string, kind ListenerKind[T]) error {
+	decode := func(raw json.RawMessage) (T, bool) {
+		var state T
+		if len(raw) == 0 {
+			return state, true
+		}
+
+		if err := json.Unmarshal(raw, &state); err != nil {
+			client.tracef("Failed to decode state of persistent listener (kind = %s): %v", name, err)
+			return state, false
+		}
+		return state, true
+	}
+
+	erased := listenerKind{}
+	if kind.OnComponent != nil {
+		erased.onComponent = func(itx *ComponentInteraction, raw json.RawMessage) bool {
+			state, ok := decode(raw)
+			return !ok || kind.OnComponent(itx, state)
+		}
+	}
+
+	if kind.OnModal != nil {
+		erased.onModal = func(itx *ModalInteraction, raw json.RawMessage) bool {
+			state, ok := decode(raw)
+			return !ok || kind.OnModal(itx, state)
+		}
+	}
+
+	if kind.OnTimeout != nil {
+		erased.onTimeout = func(raw json.RawMessage) {
+			if state, ok := decode(raw); ok {
+				kind.OnTimeout(state)
+			}
+		}
+	}
+
+	if client.listenerKinds.Has(name) {
+		return fmt.Errorf("listener kind %q is already registered", name)
+	}
+
+	client.listenerKinds.Set(name, erased)
+	return nil
+}
+
+// Persistent version of [BaseClient.AwaitComponent]. Listener is saved in client's ListenerStore together with state
+// (it has to be JSON serializable) and handled by kind registered with RegisterListenerKind, so it can be restored after restart.
+func (client *BaseClient) AwaitPersistentComponent(kind string, customIDs []string, timeout time.Duration, state any) error {
+	return client.awaitPersistent(kind, customIDs, timeout, state, false)
+}
+
+// Persistent version of [BaseClient.AwaitModal]. See [BaseClient.AwaitPersistentComponent] for details.
+func (client *BaseClient) AwaitPersistentModal(kind string, customIDs []string, timeout time.Duration, state any) error {
+	return client.awaitPersistent(kind, customIDs, timeout, state, true)
+}
+
+func (client *BaseClient) awaitPersistent(kind string, customIDs []string, timeout time.Duration, state any, modal bool) error {
+	if client.listenerStore == nil {
+		return errors.New("persistent listeners require ListenerStore in client options")
+	}
+
+	rawState, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to serialize persistent listener state: %w", err)
+	}
+
+	listener := PersistentListener{
+		ID:        newListenerID(),
+		Kind:      kind,
+		CustomIDs: slices.Clone(customIDs),
+		State:     rawState,
+		Expire:    time.Now().Add(timeout),
+		Modal:     modal,
+	}
+
+	// Save goes first - otherwise listener could finish (and delete its record) before it's even stored.
+	if err := client.listenerStore.Save(listener); err != nil {
+		return fmt.Errorf("failed to save persistent listener: %w", err)
+	}
+
+	if err := client.armPersistentListener(listener); err != nil {
+		client.deleteStoredListener(listener.ID)
+		return err
+	}
+
+	return nil
+}
+
+// Loads listeners from client's ListenerStore and re-arms them with remaining timeout.
+// Listeners that expired while app was offline get their OnTimeout callback called (and are removed from store).
+// Listeners of unknown kinds can never be handled - they're removed from store and reported in returned error.
+// Call it once, after registering listener kinds and before starting client.
+func (client *BaseClient) RestoreListeners() error {
+	if client.listenerStore == nil {
+		return errors.New("persistent listeners require ListenerStore in client options")
+	}
+
+	listeners, err := client.listenerStore.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load persistent listeners: %w", err)
+	}
+
+	var errs []error
+	restored := 0
+	for _, listener := range listeners {
+		kind, ok := client.listenerKinds.Get(listener.Kind)
+		if !ok {
+			errs = append(errs, fmt.Errorf("persistent listener %s uses unknown kind %q (removed from store)", listener.ID, listener.Kind))
+			client.deleteStoredListener(listener.ID)
+			continue
+		}
+
+		if !time.Now().Before(listener.Expire) {
+			if kind.onTimeout != nil {
+				kind.onTimeout(listener.State)
+			}
+			client.deleteStoredListener(listener.ID)
+			continue
+		}
+
+		if err := client.armPersistentListener(listener); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore persistent listener %s (removed from store): %w", listener.ID, err))
+			client.deleteStoredListener(listener.ID)
+			continue
+		}
+		restored++
+	}
+
+	client.tracef("Restored %d persistent listener(s) (%d in store).", restored, len(listeners))
+	return errors.Join(errs...)
+}
+
+// Registers listener in client's queue, wiring its handlers to kind & store.
+func (client *BaseClient) armPersistentListener(listener PersistentListener) error {
+	kind, ok := client.listenerKinds.Get(listener.Kind)
+	if !ok {
+		return fmt.Errorf("unknown listener kind %q (register it with RegisterListenerKind first)", listener.Kind)
+	}
+
+	onTimeout := func() {
+		client.deleteStoredListener(listener.ID)
+		if kind.onTimeout != nil {
+			kind.onTimeout(listener.State)
+		}
+	}
+
+	timeout := time.Until(listener.Expire)
+	if listener.Modal {
+		if kind.onModal == nil {
+			return fmt.Errorf("listener kind %q has no modal handler", listener.Kind)
+		}
+
+		return client.AwaitModal(listener.CustomIDs, timeout, func(itx *ModalInteraction) bool {
+			done := kind.onModal(itx, listener.State)
+			if done {
+				client.deleteStoredListener(listener.ID)
+			}
+			return done
+		}, onTimeout)
+	}
+
+	if kind.onComponent == nil {
+		return fmt.Errorf("listener kind %q has no component handler", listener.Kind)
+	}
+
+	return client.AwaitComponent(listener.CustomIDs, timeout, func(itx *ComponentInteraction) bool {
+		done := kind.onComponent(itx, listener.State)
+		if done {
+			client.deleteStoredListener(listener.ID)
+		}
+		return done
+	}, onTimeout)
+}
+
+func (client *BaseClient) deleteStoredListener(id string) {
+	if err := client.listenerStore.Delete(id); err != nil {
+		client.tracef("Failed to delete persistent listener %s from store: %v", id, err)
+	}
+}
+
+func newListenerID() string {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// Listener store that keeps all listeners in single JSON file. It's good enough for single instance apps
+// with moderate number of listeners - every change rewrites whole file.
+type JSONFileListenerStore struct {
+	listeners map[string]PersistentListener
+	path      string
+	mu        sync.Mutex
+	loaded    bool
+}
+
+func NewJSONFileListenerStore(path string) *JSONFileListenerStore {
+	return &JSONFileListenerStore{path: path}
+}
+
+func (store *JSONFileListenerStore) Save(listener PersistentListener) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if err := store.loadLocked(); err != nil {
+		return err
+	}
+
+	store.listeners[listener.ID] = listener
+	return store.flushLocked()
+}
+
+func (store *JSONFileListenerStore) Delete(id string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if err := store.loadLocked(); err != nil {
+		return err
+	}
+
+	if _, ok := store.listeners[id]; !ok {
+		return nil
+	}
+
+	delete(store.listeners, id)
+	return store.flushLocked()
+}
+
+func (store *JSONFileListenerStore) Load() ([]PersistentListener, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if err := store.loadLocked(); err != nil {
+		return nil, err
+	}
+
+	listeners := make([]PersistentListener, 0, len(store.listeners))
+	for _, listener := range store.listeners {
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+func (store *JSONFileListenerStore) loadLocked() error {
+	if store.loaded {
+		return nil
+	}
+
+	store.listeners = make(map[string]PersistentListener)
+	raw, err := os.ReadFile(store.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			store.loaded = true
+			return nil
+		}
+		return err
+	}
+
+	var listeners []PersistentListener
+	if err := json.Unmarshal(raw, &listeners); err != nil {
+		return fmt.Errorf("failed to parse listener store file: %w", err)
+	}
+
+	for _, listener := range listeners {
+		store.listeners[listener.ID] = listener
+	}
+
+	store.loaded = true
+	return nil
+}
+
+// Writes all listeners to temporary file and renames it, so crash mid-write never corrupts store.
+func (store *JSONFileListenerStore) flushLocked() error {
+	listeners := make([]PersistentListener, 0, len(store.listeners))
+	for _, listener := range store.listeners {
+		listeners = append(listeners, listener)
+	}
+
+	raw, err := json.Marshal(listeners)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), store.path)
+}
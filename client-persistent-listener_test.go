@@ -0,0 +1,119 @@
+package tempest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFileListenerStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "listeners.json")
+	store := NewJSONFileListenerStore(path)
+
+	kept := PersistentListener{ID: "a", Kind: "poll", CustomIDs: []string{"vote"}, State: json.RawMessage(`{"question":"Tea?"}`), Expire: time.Now().Add(time.Hour).Round(0), Modal: true}
+	for _, listener := range []PersistentListener{kept, {ID: "b", Kind: "poll", CustomIDs: []string{"skip"}}} {
+		if err := store.Save(listener); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+
+	listeners, err := NewJSONFileListenerStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listeners) != 1 {
+		t.Fatalf("expected 1 listener after reopening store, got %d", len(listeners))
+	}
+
+	got := listeners[0]
+	if got.ID != kept.ID || got.Kind != kept.Kind || !got.Modal || len(got.CustomIDs) != 1 || got.CustomIDs[0] != "vote" || string(got.State) != string(kept.State) || !got.Expire.Equal(kept.Expire) {
+		t.Errorf("listener didn't survive round-trip: %+v", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected only store file in directory, got %d entries", len(entries))
+	}
+}
+
+func TestRestoreListeners(t *testing.T) {
+	type pollState struct {
+		Question string `json:"question"`
+	}
+
+	store := NewJSONFileListenerStore(filepath.Join(t.TempDir(), "listeners.json"))
+	client := &BaseClient{
+		listenerStore:    store,
+		listenerKinds:    NewSharedMap[string, listenerKind](),
+		staticComponents: NewSharedMap[string, func(ComponentInteraction)](),
+		queuedComponents: NewSharedMap[string, *queuedComponent](),
+		sweeper:          interactionSweeper{signal: make(chan struct{}, 1)},
+	}
+
+	var timedOut []string
+	err := RegisterListenerKind(client, "poll", ListenerKind[pollState]{
+		OnComponent: func(*ComponentInteraction, pollState) bool { return true },
+		OnTimeout:   func(state pollState) { timedOut = append(timedOut, state.Question) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := []PersistentListener{
+		{ID: "expired", Kind: "poll", CustomIDs: []string{"old"}, State: json.RawMessage(`{"question":"Coffee?"}`), Expire: time.Now().Add(-time.Minute)},
+		{ID: "live", Kind: "poll", CustomIDs: []string{"vote"}, State: json.RawMessage(`{"question":"Tea?"}`), Expire: time.Now().Add(time.Hour)},
+		{ID: "unknown", Kind: "quiz", CustomIDs: []string{"answer"}, Expire: time.Now().Add(time.Hour)},
+		{ID: "modal", Kind: "poll", CustomIDs: []string{"form"}, Expire: time.Now().Add(time.Hour), Modal: true}, // Kind has no modal handler.
+	}
+
+	for _, listener := range stored {
+		if err := store.Save(listener); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = client.RestoreListeners()
+	if err == nil || !strings.Contains(err.Error(), `"quiz"`) || !strings.Contains(err.Error(), "listener modal (removed from store)") {
+		t.Errorf("expected errors about unknown listener kind and listener that failed to re-arm, got %v", err)
+	}
+
+	if len(timedOut) != 1 || timedOut[0] != "Coffee?" {
+		t.Errorf("expected OnTimeout to run for expired listener only, got %v", timedOut)
+	}
+
+	if !client.queuedComponents.Has("vote") || client.queuedComponents.Has("old") || client.queuedComponents.Has("answer") {
+		t.Error("expected only live listener to be re-armed")
+	}
+
+	listeners, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listeners) != 1 || listeners[0].ID != "live" {
+		t.Errorf("expected only live listener to stay in store, got %+v", listeners)
+	}
+
+	if err := client.AwaitPersistentModal("poll", []string{"form"}, time.Hour, pollState{}); err == nil {
+		t.Error("expected modal listener of kind without modal handler to fail")
+	}
+
+	if listeners, _ := store.Load(); len(listeners) != 1 {
+		t.Errorf("expected listener that failed to arm to be removed from store, got %+v", listeners)
+	}
+
+	client.cancelComponentListeners("vote")
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -16,6 +19,11 @@ import (
 //
 // Warning! Components handled this way will already be acknowledged.
 func (client *BaseClient) AwaitComponent(customIDs []string, timeout time.Duration, onActionFn func(itx *ComponentInteraction) bool, onTimeoutFn func()) error {
+	return client.awaitComponent(customIDs, timeout, onActionFn, onTimeoutFn, false)
+}
+
+// Registers dynamic component listener. With manualAck = true, interactions reach onActionFn unacknowledged.
+func (client *BaseClient) awaitComponent(customIDs []string, timeout time.Duration, onActionFn func(itx *ComponentInteraction) bool, onTimeoutFn func(), manualAck bool) error {
 	client.staticComponents.mu.RLock()
 	client.queuedComponents.mu.Lock()
 	defer client.staticComponents.mu.RUnlock()
@@ -31,54 +39,52 @@ func (client *BaseClient) AwaitComponent(customIDs []string, timeout time.Durati
 		}
 	}
 
+	entry := &queuedComponent{customIDs: slices.Clone(customIDs), timer: &sweeperTimer{expire: time.Now().Add(timeout)}, ManualAck: manualAck}
 	cleanup := func() {
+		client.sweeper.cancel(entry.timer)
 		client.queuedComponents.mu.Lock()
-		for _, id := range customIDs {
-			delete(client.queuedComponents.cache, id)
+		for _, id := range entry.customIDs {
+			if client.queuedComponents.cache[id] == entry {
+				delete(client.queuedComponents.cache, id)
+			}
 		}
 		client.queuedComponents.mu.Unlock()
 	}
 
 	var once sync.Once
-	handler := func(itx *ComponentInteraction) {
+	entry.Handler = func(itx *ComponentInteraction) {
 		if onActionFn(itx) {
 			once.Do(cleanup)
 		}
 	}
 
-	expire := time.Now().Add(timeout)
-
-	var timeoutFn func()
-	if onTimeoutFn != nil {
-		timeoutFn = func() {
-			once.Do(func() {
-				cleanup()
+	entry.OnTimeout = func() {
+		once.Do(func() {
+			cleanup()
+			if onTimeoutFn != nil {
 				onTimeoutFn()
-			})
-		}
-	} else {
-		timeoutFn = func() {
-			once.Do(cleanup)
-		}
+			}
+		})
 	}
 
-	for _, id := range customIDs {
-		client.queuedComponents.cache[id] = &queuedComponent{
-			Handler:   handler,
-			Expire:    expire,
-			OnTimeout: timeoutFn,
-		}
+	entry.timer.fire = entry.OnTimeout
+	client.sweeper.schedule(client, entry.timer)
+	for _, id := range entry.customIDs {
+		client.queuedComponents.cache[id] = entry
 	}
 
 	client.tracef("Registered dynamic component(s) IDs = %+v", customIDs)
-	client.sweeper.tryRun(client, expire)
-
 	return nil
 }
 
 // Mirror method to Client.AwaitComponent but for handling modal interactions.
 // Look comment on Client.AwaitComponent and see example bot/app code for more.
 func (client *BaseClient) AwaitModal(customIDs []string, timeout time.Duration, onActionFn func(itx *ModalInteraction) bool, onTimeoutFn func()) error {
+	return client.awaitModal(customIDs, timeout, onActionFn, onTimeoutFn, false)
+}
+
+// Registers dynamic modal listener. With manualAck = true, interactions reach onActionFn unacknowledged.
+func (client *BaseClient) awaitModal(customIDs []string, timeout time.Duration, onActionFn func(itx *ModalInteraction) bool, onTimeoutFn func(), manualAck bool) error {
 	client.staticModals.mu.RLock()
 	client.queuedModals.mu.Lock()
 	defer client.queuedModals.mu.Unlock()
@@ -94,61 +100,70 @@ func (client *BaseClient) AwaitModal(customIDs []string, timeout time.Duration,
 		}
 	}
 
-	var once sync.Once
-
+	entry := &queuedModal{customIDs: slices.Clone(customIDs), timer: &sweeperTimer{expire: time.Now().Add(timeout)}, ManualAck: manualAck}
 	cleanup := func() {
+		client.sweeper.cancel(entry.timer)
 		client.queuedModals.mu.Lock()
-		for _, id := range customIDs {
-			delete(client.queuedModals.cache, id)
+		for _, id := range entry.customIDs {
+			if client.queuedModals.cache[id] == entry {
+				delete(client.queuedModals.cache, id)
+			}
 		}
 		client.queuedModals.mu.Unlock()
 	}
 
-	handler := func(itx *ModalInteraction) {
+	var once sync.Once
+	entry.Handler = func(itx *ModalInteraction) {
 		if onActionFn(itx) {
 			once.Do(cleanup)
 		}
 	}
 
-	expire := time.Now().Add(timeout)
-
-	var timeoutFn func()
-	if onTimeoutFn != nil {
-		timeoutFn = func() {
-			once.Do(func() {
-				cleanup()
+	entry.OnTimeout = func() {
+		once.Do(func() {
+			cleanup()
+			if onTimeoutFn != nil {
 				onTimeoutFn()
-			})
-		}
-	} else {
-		timeoutFn = func() {
-			once.Do(cleanup)
-		}
+			}
+		})
 	}
 
-	for _, id := range customIDs {
-		client.queuedModals.cache[id] = &queuedModal{
-			Handler:   handler,
-			Expire:    expire,
-			OnTimeout: timeoutFn,
-		}
+	entry.timer.fire = entry.OnTimeout
+	client.sweeper.schedule(client, entry.timer)
+	for _, id := range entry.customIDs {
+		client.queuedModals.cache[id] = entry
 	}
 
 	client.tracef("Registered dynamic modal(s) IDs = %+v", customIDs)
-	client.sweeper.tryRun(client, expire)
-
 	return nil
 }
 
 func (client *BaseClient) RegisterCommand(cmd Command) error {
-	if client.commands.Has(cmd.Name) {
-		return errors.New("client already has registered \"" + cmd.Name + "\" slash command (name already in use)")
-	}
-
 	if cmd.Type == 0 {
 		cmd.Type = CHAT_INPUT_COMMAND_TYPE
 	}
 
+	if cmd.Type == CHAT_INPUT_COMMAND_TYPE && strings.Contains(cmd.Name, "@") {
+		return errors.New("slash command name \"" + cmd.Name + "\" cannot contain \"@\" (use RegisterSubCommand to add subcommands)")
+	}
+
+	if cmd.SlashCommandHandler != nil && cmd.SlashCommandHandlerE != nil {
+		return errors.New("slash command \"" + cmd.Name + "\" cannot have both SlashCommandHandler and SlashCommandHandlerE")
+	}
+
+	if cmd.AutoCompleteHandler != nil && cmd.AutoCompleteHandlerE != nil {
+		return errors.New("slash command \"" + cmd.Name + "\" cannot have both AutoCompleteHandler and AutoCompleteHandlerE")
+	}
+
+	if err := checkContextMenuHandlers(cmd); err != nil {
+		return err
+	}
+
+	key := commandRegistryKey(cmd.Type, cmd.Name)
+	if client.commands.Has(key) {
+		return errors.New("client already has registered \"" + cmd.Name + "\" " + commandTypeName(cmd.Type) + " command (name already in use)")
+	}
+
 	if cmd.ApplicationID == 0 {
 		cmd.ApplicationID = client.ApplicationID
 	}
@@ -157,15 +172,113 @@ func (client *BaseClient) RegisterCommand(cmd Command) error {
 		cmd.Contexts = client.commandContexts
 	}
 
-	client.commands.Set(cmd.Name, cmd)
-	client.tracef("Registered %s command.", cmd.Name)
+	if client.catalog != nil {
+		client.catalog.localizeCommand(&cmd, key)
+	}
+
+	if errs := validateCommand(nil, cmd); len(errs) != 0 {
+		return errs
+	}
+
+	client.commands.Set(key, cmd)
+	client.tracef("Registered %s command.", key)
 
 	return nil
 }
 
+// Registers user command, available in context menu of users (Apps section). Command needs UserCommandHandler,
+// which receives user that command was used on. It's stored in registry as "user:<name>", so it can share name with slash & message commands.
+func (client *BaseClient) RegisterUserCommand(cmd Command) error {
+	if cmd.Type != 0 && cmd.Type != USER_COMMAND_TYPE {
+		return errors.New("cannot register \"" + cmd.Name + "\" " + commandTypeName(cmd.Type) + " command as user command")
+	}
+
+	if cmd.UserCommandHandler == nil {
+		return errors.New("user command \"" + cmd.Name + "\" is missing UserCommandHandler")
+	}
+
+	cmd.Type = USER_COMMAND_TYPE
+	return client.RegisterCommand(cmd)
+}
+
+// Registers message command, available in context menu of messages (Apps section). Command needs MessageCommandHandler,
+// which receives message that command was used on. It's stored in registry as "message:<name>", so it can share name with slash & user commands.
+func (client *BaseClient) RegisterMessageCommand(cmd Command) error {
+	if cmd.Type != 0 && cmd.Type != MESSAGE_COMMAND_TYPE {
+		return errors.New("cannot register \"" + cmd.Name + "\" " + commandTypeName(cmd.Type) + " command as message command")
+	}
+
+	if cmd.MessageCommandHandler == nil {
+		return errors.New("message command \"" + cmd.Name + "\" is missing MessageCommandHandler")
+	}
+
+	cmd.Type = MESSAGE_COMMAND_TYPE
+	return client.RegisterCommand(cmd)
+}
+
+// Checks that context menu handlers are only used by matching command types (and never together with other handlers).
+func checkContextMenuHandlers(cmd Command) error {
+	name := "\"" + cmd.Name + "\" " + commandTypeName(cmd.Type) + " command"
+	if cmd.UserCommandHandler != nil && cmd.Type != USER_COMMAND_TYPE {
+		return errors.New(name + " cannot have UserCommandHandler (use RegisterUserCommand for user commands)")
+	}
+
+	if cmd.MessageCommandHandler != nil && cmd.Type != MESSAGE_COMMAND_TYPE {
+		return errors.New(name + " cannot have MessageCommandHandler (use RegisterMessageCommand for message commands)")
+	}
+
+	if (cmd.UserCommandHandler != nil || cmd.MessageCommandHandler != nil) && (cmd.SlashCommandHandler != nil || cmd.SlashCommandHandlerE != nil) {
+		return errors.New(name + " cannot have both context menu and slash command handlers")
+	}
+
+	if (cmd.Type == USER_COMMAND_TYPE || cmd.Type == MESSAGE_COMMAND_TYPE) && (cmd.AutoCompleteHandler != nil || cmd.AutoCompleteHandlerE != nil) {
+		return errors.New(name + " cannot have auto complete handler (context menu commands have no options)")
+	}
+
+	return nil
+}
+
+// Returns key of root command in registry. Slash commands use plain names (and "parent@sub" paths for subcommands),
+// other command types are prefixed with their type, e.g. "user:Show avatar" or "message:Report".
+// Slash command names cannot contain ":", so both kinds of keys never collide.
+func commandRegistryKey(kind CommandType, name string) string {
+	switch kind {
+	case 0, CHAT_INPUT_COMMAND_TYPE:
+		return name
+	case USER_COMMAND_TYPE:
+		return "user:" + name
+	case MESSAGE_COMMAND_TYPE:
+		return "message:" + name
+	case PRIMARY_ENTRY_POINT_COMMAND_TYPE:
+		return "entry:" + name
+	}
+	return strconv.Itoa(int(kind)) + ":" + name
+}
+
+// Reports whether registry key belongs to slash command (or its subcommand/group).
+func isSlashCommandKey(key string) bool {
+	return !strings.Contains(key, ":")
+}
+
+// Registers subcommand under parent command or subcommand group.
+// Use "parentName@groupName" as parent name to nest subcommand inside group registered with [BaseClient.RegisterSubCommandGroup] (e.g. "config@logging").
+//
+// Parent command cannot have its own handler or options once it holds subcommands - Discord never invokes such command directly.
 func (client *BaseClient) RegisterSubCommand(subCommand Command, parentCommandName string) error {
-	if !client.commands.Has(parentCommandName) {
-		return errors.New("missing \"" + parentCommandName + "\" slash command in registry (parent command needs to be registered in client before adding subcommands)")
+	if err := client.checkSubCommandParent(parentCommandName, subCommand.Name); err != nil {
+		return err
+	}
+
+	if subCommand.SlashCommandHandler != nil && subCommand.SlashCommandHandlerE != nil {
+		return errors.New("subcommand \"" + subCommand.Name + "\" cannot have both SlashCommandHandler and SlashCommandHandlerE")
+	}
+
+	if subCommand.AutoCompleteHandler != nil && subCommand.AutoCompleteHandlerE != nil {
+		return errors.New("subcommand \"" + subCommand.Name + "\" cannot have both AutoCompleteHandler and AutoCompleteHandlerE")
+	}
+
+	if subCommand.UserCommandHandler != nil || subCommand.MessageCommandHandler != nil {
+		return errors.New("subcommand \"" + subCommand.Name + "\" cannot have context menu handlers (UserCommandHandler or MessageCommandHandler)")
 	}
 
 	finalName := parentCommandName + "@" + subCommand.Name
@@ -177,6 +290,10 @@ func (client *BaseClient) RegisterSubCommand(subCommand Command, parentCommandNa
 		subCommand.Type = CHAT_INPUT_COMMAND_TYPE
 	}
 
+	if subCommand.Type != CHAT_INPUT_COMMAND_TYPE {
+		return errors.New("subcommand \"" + subCommand.Name + "\" must be a slash command (got " + commandTypeName(subCommand.Type) + " command type)")
+	}
+
 	if subCommand.ApplicationID == 0 {
 		subCommand.ApplicationID = client.ApplicationID
 	}
@@ -185,12 +302,92 @@ func (client *BaseClient) RegisterSubCommand(subCommand Command, parentCommandNa
 		subCommand.Contexts = client.commandContexts
 	}
 
+	if client.catalog != nil {
+		client.catalog.localizeCommand(&subCommand, strings.ReplaceAll(finalName, "@", "."))
+	}
+
+	if errs := validateSubCommand(parentCommandName, subCommand, SUB_COMMAND_OPTION_TYPE); len(errs) != 0 {
+		return errs
+	}
+
 	client.commands.Set(finalName, subCommand)
 	client.tracef("Registered %s sub command (part of %s command).", finalName, parentCommandName)
 
 	return nil
 }
 
+// Registers subcommand group (second nesting level, like "logging" in "/config logging channel") under parent command.
+// Group only holds name, description & their localizations - handlers and options belong to subcommands
+// registered later with [BaseClient.RegisterSubCommand] using "parentName@groupName" as parent name.
+func (client *BaseClient) RegisterSubCommandGroup(group Command, parentCommandName string) error {
+	if strings.Contains(parentCommandName, "@") {
+		return errors.New("subcommand group \"" + group.Name + "\" can only be registered directly under root command (Discord allows up to two nesting levels)")
+	}
+
+	if group.SlashCommandHandler != nil || group.SlashCommandHandlerE != nil || group.AutoCompleteHandler != nil || group.AutoCompleteHandlerE != nil || group.UserCommandHandler != nil || group.MessageCommandHandler != nil || len(group.Options) != 0 {
+		return errors.New("subcommand group \"" + group.Name + "\" cannot have its own handlers or options (register subcommands inside it instead)")
+	}
+
+	if err := client.checkSubCommandParent(parentCommandName, group.Name); err != nil {
+		return err
+	}
+
+	finalName := parentCommandName + "@" + group.Name
+	if client.commands.Has(finalName) {
+		return errors.New("client already has registered \"" + finalName + "\" slash command (name for subcommand group is already in use)")
+	}
+
+	if client.catalog != nil {
+		client.catalog.localizeCommand(&group, strings.ReplaceAll(finalName, "@", "."))
+	}
+
+	if errs := validateSubCommand(parentCommandName, group, SUB_COMMAND_GROUP_OPTION_TYPE); len(errs) != 0 {
+		return errs
+	}
+
+	group.Type, group.subCommandGroup = CHAT_INPUT_COMMAND_TYPE, true
+	client.commands.Set(finalName, group)
+	client.tracef("Registered %s sub command group (part of %s command).", finalName, parentCommandName)
+
+	return nil
+}
+
+// Validates that parent path points at root command (or subcommand group) that can hold given child.
+func (client *BaseClient) checkSubCommandParent(parentCommandName string, childName string) error {
+	rootName, groupName, nested := strings.Cut(parentCommandName, "@")
+	if strings.Contains(groupName, "@") {
+		return errors.New("invalid parent \"" + parentCommandName + "\" for \"" + childName + "\" subcommand (Discord allows up to two nesting levels)")
+	}
+
+	root, available := client.commands.Get(rootName)
+	if !available {
+		return errors.New("missing \"" + rootName + "\" slash command in registry (parent command needs to be registered in client before adding subcommands)")
+	}
+
+	if root.Type != CHAT_INPUT_COMMAND_TYPE {
+		return errors.New("cannot register \"" + childName + "\" under \"" + rootName + "\" command - only slash commands can have subcommands")
+	}
+
+	if root.SlashCommandHandler != nil || root.SlashCommandHandlerE != nil || root.AutoCompleteHandler != nil || root.AutoCompleteHandlerE != nil || len(root.Options) != 0 {
+		return errors.New("cannot register \"" + childName + "\" under \"" + rootName + "\" slash command - it already has own handler or options (Discord doesn't allow to invoke commands with subcommands directly)")
+	}
+
+	if !nested {
+		return nil
+	}
+
+	group, available := client.commands.Get(parentCommandName)
+	if !available {
+		return errors.New("missing \"" + parentCommandName + "\" subcommand group in registry (group needs to be registered in client before adding subcommands)")
+	}
+
+	if !group.subCommandGroup {
+		return errors.New("\"" + parentCommandName + "\" is a subcommand, not subcommand group (cannot nest \"" + childName + "\" inside it)")
+	}
+
+	return nil
+}
+
 // Bind function to all components with matching custom ids. App will automatically run bound function whenever receiving component interaction with matching custom id.
 func (client *BaseClient) RegisterComponent(customIDs []string, handler func(ComponentInteraction)) error {
 	client.staticComponents.mu.Lock()
@@ -206,6 +403,10 @@ func (client *BaseClient) RegisterComponent(customIDs []string, handler func(Com
 		if _, ok := client.queuedComponents.cache[id]; ok {
 			return fmt.Errorf("client already has registered dynamic (queued) component with custom ID %q (custom id already in use elsewhere)", id)
 		}
+
+		if err := checkStaticRouteConflict(client.componentRoutes, id); err != nil {
+			return err
+		}
 	}
 
 	for _, key := range customIDs {
@@ -231,6 +432,10 @@ func (client *BaseClient) RegisterModal(customID string, handler func(ModalInter
 		return fmt.Errorf("client already has registered dynamic (queued) modal with custom ID %q (custom id already in use elsewhere)", customID)
 	}
 
+	if err := checkStaticRouteConflict(client.modalRoutes, customID); err != nil {
+		return err
+	}
+
 	client.staticModals.cache[customID] = handler
 	client.tracef("Registered static modal handler for custom ID = %s", customID)
 
@@ -279,18 +484,67 @@ func (client *BaseClient) DeleteModal(customIDs []string) error {
 	return nil
 }
 
+// Finds command by its registry key - name for slash commands, "parentName@subcommandName" for subcommands
+// and "user:<name>" or "message:<name>" for context menu commands.
 func (client *BaseClient) FindCommand(cmdName string) (Command, bool) {
 	return client.commands.Get(cmdName)
 }
 
+// Finds root command by ID assigned by Discord. Commands get indexed once they're synced or fetched from Discord,
+// using IDs from every scope (global & each guild).
+func (client *BaseClient) FindCommandByID(commandID Snowflake) (Command, bool) {
+	name, available := client.commandIDs.Get(commandID)
+	if !available {
+		return Command{}, false
+	}
+	return client.commands.Get(name)
+}
+
+// Renders clickable mention of registered (and already synced) command.
+// Pass subcommand group and/or subcommand names after root command name to mention nested command,
+// e.g. CommandMention("config", "logging", "channel") returns "</config logging channel:123>".
+func (client *BaseClient) CommandMention(names ...string) (string, error) {
+	if len(names) == 0 {
+		return "", errors.New("missing command name")
+	}
+
+	path := strings.Join(names, "@")
+	if !client.commands.Has(path) {
+		return "", errors.New("missing \"" + path + "\" command in registry")
+	}
+
+	root, _ := client.commands.Get(names[0])
+	if root.ID == 0 {
+		return "", errors.New("\"" + names[0] + "\" command has no ID yet (sync or fetch commands first)")
+	}
+
+	return root.Mention(names[1:]...), nil
+}
+
 // Removes a command from the registry.
-// If the command is a subcommand, the name must be formatted as "parentName@subcommandName" (e.g. "inventory@use").
+// If the command is a subcommand, the name must be formatted as "parentName@subcommandName" (e.g. "inventory@use")
+// or "parentName@groupName@subcommandName" for subcommands inside groups (e.g. "config@logging@channel").
+// Context menu commands are removed by their registry keys: "user:<name>" or "message:<name>".
+// Removing slash command (or subcommand group) also removes all of its subcommands.
 func (client *BaseClient) DeleteCommand(name string) {
-	client.commands.Delete(name)
+	if !isSlashCommandKey(name) {
+		client.commands.Delete(name)
+	} else {
+		prefix := name + "@"
+		client.commands.Sweep(func(key string, _ Command) bool {
+			return key == name || strings.HasPrefix(key, prefix)
+		})
+	}
+
+	client.commandIDs.Sweep(func(_ Snowflake, root string) bool {
+		return root == name
+	})
 }
 
 // Returns an iterator over all registered command names and their configurations.
-// Subcommands are returned with names formatted as "parentName@subcommandName" (e.g. "inventory@use").
+// Subcommands are returned with names formatted as "parentName@subcommandName" (e.g. "inventory@use"),
+// subcommand groups and their subcommands as "parentName@groupName" and "parentName@groupName@subcommandName".
+// Context menu commands are returned as "user:<name>" and "message:<name>".
 func (client *BaseClient) RegisteredCommands() iter.Seq2[string, Command] {
 	return client.commands.Entries()
 }
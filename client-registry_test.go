@@ -0,0 +1,202 @@
+package tempest
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestContextMenuCommands(t *testing.T) {
+	client := &BaseClient{commands: NewSharedMap[string, Command](), commandIDs: NewSharedMap[Snowflake, string]()}
+
+	var target Snowflake
+	register := []func() error{
+		func() error {
+			return client.RegisterCommand(Command{Name: "report", Description: "Reports something.", SlashCommandHandler: func(*CommandInteraction) {}})
+		},
+		func() error {
+			return client.RegisterUserCommand(Command{Name: "report", UserCommandHandler: func(_ *CommandInteraction, user User, _ *Member) error {
+				target = user.ID
+				return nil
+			}})
+		},
+		func() error {
+			return client.RegisterMessageCommand(Command{Name: "report", MessageCommandHandler: func(*CommandInteraction, Message) error { return nil }})
+		},
+	}
+
+	for _, fn := range register {
+		if err := fn(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.RegisterUserCommand(Command{Name: "report", UserCommandHandler: func(*CommandInteraction, User, *Member) error { return nil }}); err == nil {
+		t.Error("expected error when registering user command with duplicated name")
+	}
+
+	if err := client.RegisterCommand(Command{Name: "ping", Description: "Pong.", MessageCommandHandler: func(*CommandInteraction, Message) error { return nil }}); err == nil {
+		t.Error("expected error when slash command has message command handler")
+	}
+
+	if commands := parseCommandsForDiscordAPI(client.commands, nil, false); len(commands) != 3 {
+		t.Fatalf("expected 3 commands for Discord API, got %d", len(commands))
+	}
+
+	client.storeCommandIDs([]Command{{ID: 1, Name: "report", Type: CHAT_INPUT_COMMAND_TYPE}, {ID: 2, Name: "report", Type: USER_COMMAND_TYPE}})
+	if cmd, ok := client.FindCommandByID(2); !ok || cmd.Type != USER_COMMAND_TYPE {
+		t.Errorf("expected command ID 2 to point at user command, got %+v", cmd)
+	}
+
+	itx := CommandInteraction{
+		Interaction: &Interaction{},
+		Data: CommandInteractionData{
+			Name:     "report",
+			Type:     USER_COMMAND_TYPE,
+			TargetID: 42,
+			Resolved: &InteractionDataResolved{Users: map[Snowflake]User{42: {ID: 42}}},
+		},
+	}
+
+	itx, cmd, ok := client.handleInteraction(itx)
+	if !ok || cmd.UserCommandHandler == nil || itx.Data.Name != "report" {
+		t.Fatalf("expected interaction to reach user command with name kept as sent by Discord, got %q (found = %t)", itx.Data.Name, ok)
+	}
+
+	var invoked string
+	client.Use(func(next Handler) Handler {
+		return func(inv *Invocation) error {
+			invoked = inv.Name
+			return next(inv)
+		}
+	})
+
+	client.runCommand(&itx, cmd)
+	if target != 42 {
+		t.Errorf("expected handler to receive target user 42, got %d", target)
+	}
+
+	if invoked != "user:report" {
+		t.Errorf("expected invocation to carry registry key \"user:report\", got %q", invoked)
+	}
+}
+
+func TestSubCommandGroups(t *testing.T) {
+	client := &BaseClient{commands: NewSharedMap[string, Command]()}
+	handler := func(*CommandInteraction) {}
+
+	steps := []func() error{
+		func() error { return client.RegisterCommand(Command{Name: "config", Description: "Configures bot."}) },
+		func() error {
+			return client.RegisterSubCommand(Command{Name: "reset", Description: "Resets config.", SlashCommandHandler: handler}, "config")
+		},
+		func() error {
+			return client.RegisterSubCommandGroup(Command{Name: "logging", Description: "Logging settings."}, "config")
+		},
+		func() error {
+			return client.RegisterSubCommand(Command{Name: "channel", Description: "Sets log channel.", SlashCommandHandler: handler}, "config@logging")
+		},
+	}
+
+	for _, fn := range steps {
+		if err := fn(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.RegisterSubCommand(Command{Name: "nested", Description: "Nested.", SlashCommandHandler: handler}, "config@reset"); err == nil {
+		t.Error("expected error when nesting subcommand inside another subcommand")
+	}
+
+	if err := client.RegisterSubCommand(Command{Name: "avatar", Type: USER_COMMAND_TYPE, SlashCommandHandler: handler}, "config"); err == nil {
+		t.Error("expected error when registering user command as subcommand")
+	}
+
+	itx, cmd, ok := client.handleInteraction(CommandInteraction{
+		Interaction: &Interaction{},
+		Data: CommandInteractionData{
+			Name: "config",
+			Type: CHAT_INPUT_COMMAND_TYPE,
+			Options: []CommandInteractionOption{{
+				Name: "logging",
+				Type: SUB_COMMAND_GROUP_OPTION_TYPE,
+				Options: []CommandInteractionOption{{
+					Name:    "channel",
+					Type:    SUB_COMMAND_OPTION_TYPE,
+					Options: []CommandInteractionOption{{Name: "target", Type: CHANNEL_OPTION_TYPE, Value: "1"}},
+				}},
+			}},
+		},
+	})
+	if !ok || cmd.Name != "channel" || itx.Data.Name != "config@logging@channel" || len(itx.Data.Options) != 1 || itx.Data.Options[0].Name != "target" {
+		t.Fatalf("expected group invocation to be flattened into subcommand, got %q with options %+v", itx.Data.Name, itx.Data.Options)
+	}
+
+	commands := parseCommandsForDiscordAPI(client.commands, nil, false)
+	if len(commands) != 1 {
+		t.Fatalf("expected single root command, got %d", len(commands))
+	}
+
+	options := commands[0].Options
+	if len(options) != 2 || options[0].Name != "logging" || options[0].Type != SUB_COMMAND_GROUP_OPTION_TYPE || options[1].Name != "reset" || options[1].Type != SUB_COMMAND_OPTION_TYPE {
+		t.Fatalf("unexpected root options: %+v", options)
+	}
+
+	if nested := options[0].Options; len(nested) != 1 || nested[0].Name != "channel" || nested[0].Type != SUB_COMMAND_OPTION_TYPE {
+		t.Fatalf("unexpected group options: %+v", nested)
+	}
+
+	raw, err := json.Marshal(commands[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(raw), `"type":1`) || strings.Contains(string(raw), "subCommandGroup") {
+		t.Errorf("unexpected serialized command: %s", raw)
+	}
+}
+
+func TestDeleteCommandRemovesSubCommands(t *testing.T) {
+	client := &BaseClient{commands: NewSharedMap[string, Command](), commandIDs: NewSharedMap[Snowflake, string]()}
+	handler := func(*CommandInteraction) {}
+
+	register := func() {
+		steps := []func() error{
+			func() error { return client.RegisterCommand(Command{Name: "config", Description: "Configures bot."}) },
+			func() error {
+				return client.RegisterSubCommandGroup(Command{Name: "logging", Description: "Logging settings."}, "config")
+			},
+			func() error {
+				return client.RegisterSubCommand(Command{Name: "channel", Description: "Sets log channel.", SlashCommandHandler: handler}, "config@logging")
+			},
+			func() error {
+				return client.RegisterCommand(Command{Name: "config", Type: USER_COMMAND_TYPE, UserCommandHandler: func(*CommandInteraction, User, *Member) error { return nil }})
+			},
+		}
+
+		for _, fn := range steps {
+			if err := fn(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	register()
+	client.DeleteCommand("config@logging")
+	if client.commands.Has("config@logging") || client.commands.Has("config@logging@channel") || !client.commands.Has("config") {
+		t.Fatal("expected deleted group to take its subcommands with it")
+	}
+
+	client.DeleteCommand("config")
+	if client.commands.Size() != 1 || !client.commands.Has(commandRegistryKey(USER_COMMAND_TYPE, "config")) {
+		t.Fatalf("expected only user command to stay registered, got %v", slices.Collect(client.commands.Keys()))
+	}
+
+	client.DeleteCommand(commandRegistryKey(USER_COMMAND_TYPE, "config"))
+	register() // Would fail on leftover subcommands.
+
+	if client.commands.Size() != 4 {
+		t.Errorf("expected re-registered commands, got %v", slices.Collect(client.commands.Keys()))
+	}
+}
@@ -0,0 +1,316 @@
+package tempest
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+)
+
+// Separator of custom ID segments used by routes.
+const ROUTE_SEGMENT_SEPARATOR = ":"
+
+// Trie of custom ID patterns. Each node represents single segment of custom ID.
+//
+// Pattern segments can be:
+//   - literal text (like "ticket") that must match exactly,
+//   - {name} that matches any single, non-empty segment and stores it as param,
+//   - {name...} or * (only as last segment) that matches all remaining segments (at least one).
+//
+// When multiple routes match, the most specific one wins: literal segments beat params and params beat catch-all.
+type customIDRouter[H any] struct {
+	root routeNode[H]
+	mu   sync.RWMutex
+}
+
+type routeNode[H any] struct {
+	handler   *H
+	children  map[string]*routeNode[H]
+	param     *routeNode[H]
+	catchAll  *routeNode[H]
+	paramName string // Name of param (for param & catch-all nodes).
+	pattern   string // Full pattern of route ending at this node.
+}
+
+type routeMatch[H any] struct {
+	handler H
+	params  map[string]string
+	pattern string
+}
+
+func parseRouteSegment(segment string) (kind string, name string, err error) {
+	if segment == "*" {
+		return "catch-all", "", nil
+	}
+
+	if !strings.HasPrefix(segment, "{") && !strings.HasSuffix(segment, "}") {
+		if strings.ContainsAny(segment, "{}") {
+			return "", "", fmt.Errorf("invalid route segment %q (braces can only wrap whole segment)", segment)
+		}
+		return "literal", segment, nil
+	}
+
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") || len(segment) < 3 {
+		return "", "", fmt.Errorf("invalid route segment %q", segment)
+	}
+
+	name = segment[1 : len(segment)-1]
+	if trimmed, ok := strings.CutSuffix(name, "..."); ok {
+		if trimmed == "" {
+			return "", "", fmt.Errorf("invalid route segment %q (missing param name)", segment)
+		}
+		return "catch-all", trimmed, nil
+	}
+
+	if strings.ContainsAny(name, "{}") {
+		return "", "", fmt.Errorf("invalid route segment %q", segment)
+	}
+	return "param", name, nil
+}
+
+func (router *customIDRouter[H]) add(pattern string, handler H) error {
+	if pattern == "" {
+		return errors.New("route pattern cannot be empty")
+	}
+
+	segments := strings.Split(pattern, ROUTE_SEGMENT_SEPARATOR)
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	node := &router.root
+	seen := make(map[string]struct{}, len(segments))
+	for i, segment := range segments {
+		kind, name, err := parseRouteSegment(segment)
+		if err != nil {
+			return err
+		}
+
+		if kind != "literal" && name != "" {
+			if _, exists := seen[name]; exists {
+				return fmt.Errorf("route pattern %q uses param %q more than once", pattern, name)
+			}
+			seen[name] = struct{}{}
+		}
+
+		switch kind {
+		case "literal":
+			if node.children == nil {
+				node.children = make(map[string]*routeNode[H])
+			}
+
+			next, ok := node.children[name]
+			if !ok {
+				next = &routeNode[H]{}
+				node.children[name] = next
+			}
+			node = next
+		case "param":
+			if node.param == nil {
+				node.param = &routeNode[H]{paramName: name}
+			} else if node.param.paramName != name {
+				return fmt.Errorf("route pattern %q conflicts with existing route using {%s} param in the same place", pattern, node.param.paramName)
+			}
+			node = node.param
+		case "catch-all":
+			if i != len(segments)-1 {
+				return fmt.Errorf("route pattern %q has catch-all segment that isn't the last one", pattern)
+			}
+
+			if node.catchAll == nil {
+				node.catchAll = &routeNode[H]{paramName: name}
+			} else if node.catchAll.paramName != name {
+				return fmt.Errorf("route pattern %q conflicts with existing catch-all route %q", pattern, node.catchAll.pattern)
+			}
+			node = node.catchAll
+		}
+	}
+
+	if node.handler != nil {
+		return fmt.Errorf("route pattern %q is already registered (as %q)", pattern, node.pattern)
+	}
+
+	node.handler = &handler
+	node.pattern = pattern
+	return nil
+}
+
+func (router *customIDRouter[H]) remove(pattern string) bool {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	node := &router.root
+	for _, segment := range strings.Split(pattern, ROUTE_SEGMENT_SEPARATOR) {
+		kind, name, err := parseRouteSegment(segment)
+		if err != nil {
+			return false
+		}
+
+		switch kind {
+		case "literal":
+			node = node.children[name]
+		case "param":
+			node = node.param
+		case "catch-all":
+			node = node.catchAll
+		}
+
+		if node == nil {
+			return false
+		}
+	}
+
+	if node.handler == nil || node.pattern != pattern {
+		return false
+	}
+
+	node.handler = nil
+	node.pattern = ""
+	return true
+}
+
+// Finds the most specific route matching given custom ID.
+func (router *customIDRouter[H]) match(customID string) (routeMatch[H], bool) {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+
+	segments := strings.Split(customID, ROUTE_SEGMENT_SEPARATOR)
+	node := router.root.lookup(segments)
+	if node == nil {
+		return routeMatch[H]{}, false
+	}
+
+	return routeMatch[H]{handler: *node.handler, params: extractRouteParams(node.pattern, segments), pattern: node.pattern}, true
+}
+
+// Walks trie depth-first, trying literal, param and catch-all children (in that order) at each segment.
+func (node *routeNode[H]) lookup(segments []string) *routeNode[H] {
+	if len(segments) == 0 {
+		if node.handler != nil {
+			return node
+		}
+		return nil
+	}
+
+	segment := segments[0]
+	if next, ok := node.children[segment]; ok {
+		if found := next.lookup(segments[1:]); found != nil {
+			return found
+		}
+	}
+
+	if node.param != nil && segment != "" {
+		if found := node.param.lookup(segments[1:]); found != nil {
+			return found
+		}
+	}
+
+	if node.catchAll != nil && node.catchAll.handler != nil {
+		return node.catchAll
+	}
+
+	return nil
+}
+
+// Re-reads params from matched pattern (params are rare and it keeps trie walk allocation free).
+func extractRouteParams(pattern string, segments []string) map[string]string {
+	patternSegments := strings.Split(pattern, ROUTE_SEGMENT_SEPARATOR)
+	var params map[string]string
+
+	for i, segment := range patternSegments {
+		kind, name, _ := parseRouteSegment(segment)
+		if kind == "literal" || name == "" {
+			continue
+		}
+
+		if params == nil {
+			params = make(map[string]string, len(patternSegments)-i)
+		}
+
+		if kind == "catch-all" {
+			params[name] = strings.Join(segments[i:], ROUTE_SEGMENT_SEPARATOR)
+			break
+		}
+		params[name] = segments[i]
+	}
+
+	return params
+}
+
+// Registers handler for all components with custom ID matching pattern, like "ticket:close:{ticketID}" or "shop:*".
+// Use itx.Param("ticketID") (or itx.ParamSnowflake) to read params inside handler.
+//
+// Routes are checked after static and dynamic (awaited) components, but before global component handler.
+// Registering pattern that would match already registered static custom ID returns error.
+func (client *BaseClient) RegisterComponentRoute(pattern string, handler func(itx *ComponentInteraction)) error {
+	if err := checkRouteConflicts(pattern, client.staticComponents.Keys()); err != nil {
+		return err
+	}
+
+	if err := client.componentRoutes.add(pattern, handler); err != nil {
+		return err
+	}
+
+	client.tracef("Registered component route %q.", pattern)
+	return nil
+}
+
+// Registers handler for all modals with custom ID matching pattern. See [BaseClient.RegisterComponentRoute] for pattern syntax.
+func (client *BaseClient) RegisterModalRoute(pattern string, handler func(itx *ModalInteraction)) error {
+	if err := checkRouteConflicts(pattern, client.staticModals.Keys()); err != nil {
+		return err
+	}
+
+	if err := client.modalRoutes.add(pattern, handler); err != nil {
+		return err
+	}
+
+	client.tracef("Registered modal route %q.", pattern)
+	return nil
+}
+
+// Removes component route registered with exactly the same pattern.
+func (client *BaseClient) DeleteComponentRoute(pattern string) bool {
+	return client.componentRoutes.remove(pattern)
+}
+
+// Removes modal route registered with exactly the same pattern.
+func (client *BaseClient) DeleteModalRoute(pattern string) bool {
+	return client.modalRoutes.remove(pattern)
+}
+
+func checkRouteConflicts(pattern string, staticIDs iter.Seq[string]) error {
+	probe := customIDRouter[struct{}]{}
+	if err := probe.add(pattern, struct{}{}); err != nil {
+		return err
+	}
+
+	for id := range staticIDs {
+		if _, ok := probe.match(id); ok {
+			return fmt.Errorf("route pattern %q conflicts with static custom ID %q (static handler would always win)", pattern, id)
+		}
+	}
+	return nil
+}
+
+// Checks whether static custom ID would shadow already registered route.
+func checkStaticRouteConflict[H any](router *customIDRouter[H], customID string) error {
+	if match, ok := router.match(customID); ok {
+		return fmt.Errorf("custom ID %q conflicts with registered route %q", customID, match.pattern)
+	}
+	return nil
+}
+
+// Returns value of route param (see [BaseClient.RegisterComponentRoute]) or empty string when there's no such param.
+func (itx *Interaction) Param(name string) string {
+	return itx.params[name]
+}
+
+// Returns value of route param parsed as Snowflake.
+func (itx *Interaction) ParamSnowflake(name string) (Snowflake, error) {
+	raw, ok := itx.params[name]
+	if !ok {
+		return 0, fmt.Errorf("missing %q route param", name)
+	}
+	return StringToSnowflake(raw)
+}
@@ -0,0 +1,50 @@
+package tempest
+
+import "testing"
+
+func TestCustomIDRouter(t *testing.T) {
+	router := customIDRouter[string]{}
+	for _, pattern := range []string{"ticket:close:{ticketID}", "ticket:close:all", "ticket:{action}:{ticketID}", "shop:{page...}", "*"} {
+		if err := router.add(pattern, pattern); err != nil {
+			t.Fatalf("failed to add %q: %v", pattern, err)
+		}
+	}
+
+	cases := []struct {
+		customID string
+		pattern  string
+		params   map[string]string
+	}{
+		{"ticket:close:all", "ticket:close:all", nil},
+		{"ticket:close:123", "ticket:close:{ticketID}", map[string]string{"ticketID": "123"}},
+		{"ticket:open:5", "ticket:{action}:{ticketID}", map[string]string{"action": "open", "ticketID": "5"}},
+		{"shop:food:2", "shop:{page...}", map[string]string{"page": "food:2"}},
+		{"ticket:close", "*", nil},
+	}
+
+	for _, tc := range cases {
+		match, ok := router.match(tc.customID)
+		if !ok || match.pattern != tc.pattern {
+			t.Errorf("%q: expected %q route, got %q (matched = %v)", tc.customID, tc.pattern, match.pattern, ok)
+			continue
+		}
+
+		for key, value := range tc.params {
+			if match.params[key] != value {
+				t.Errorf("%q: expected param %s = %q, got %q", tc.customID, key, value, match.params[key])
+			}
+		}
+	}
+
+	if err := router.add("ticket:close:{id}", "dup"); err == nil {
+		t.Error("expected conflict for param with different name in the same place")
+	}
+
+	if err := router.add("a:*:b", "bad"); err == nil {
+		t.Error("expected error for catch-all segment in the middle of pattern")
+	}
+
+	if err := checkRouteConflicts("hello:{name}", func(yield func(string) bool) { yield("hello:world") }); err == nil {
+		t.Error("expected conflict with static custom ID")
+	}
+}
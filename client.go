@@ -5,8 +5,11 @@ import (
 	"errors"
 	"io"
 	"log"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 )
 
 // BaseClient is the core tempest entrypoint. It's used to create either HTTP or Gateway clients.
@@ -18,31 +21,66 @@ type BaseClient struct {
 	traceLogger *log.Logger // Inherited from HTTPClient or GatewayClient
 
 	commands         *SharedMap[string, Command]
+	commandIDs       *SharedMap[Snowflake, string] // Discord command ID (from any scope) -> root command name.
 	staticComponents *SharedMap[string, func(ComponentInteraction)]
 
-	preCommandHandler  func(cmd Command, itx *CommandInteraction) bool
-	postCommandHandler func(cmd Command, itx *CommandInteraction)
-	componentHandler   func(itx *ComponentInteraction)
-	modalHandler       func(itx *ModalInteraction)
+	componentHandler func(itx *ComponentInteraction)
+	modalHandler     func(itx *ModalInteraction)
+	errorHandler     func(itx *Interaction, err error)
+
+	cooldownStore         CooldownStore
+	concurrency           *SharedMap[string, uint32]
+	cooldownHandler       func(itx *CommandInteraction, retryAfter time.Duration)
+	maxConcurrencyHandler func(itx *CommandInteraction)
+
+	listenerStore ListenerStore
+	listenerKinds *SharedMap[string, listenerKind]
+	catalog       *Catalog
+
+	commandMiddlewares   middlewareList
+	componentMiddlewares middlewareList
+	modalMiddlewares     middlewareList
 
 	queuedComponents *SharedMap[string, *queuedComponent]
 	queuedModals     *SharedMap[string, *queuedModal]
+	componentRoutes  *customIDRouter[func(itx *ComponentInteraction)]
+	modalRoutes      *customIDRouter[func(itx *ModalInteraction)]
 	Rest             *Rest
+	autoDefer        AutoDeferOptions
 	commandContexts  []InteractionContextType
 	ApplicationID    Snowflake
 	trace            bool
 }
 
 type BaseClientOptions struct {
-	PreCommandHook   func(cmd Command, itx *CommandInteraction) bool // Function that runs before each command. Return type signals whether to continue command execution (return with false to stop early).
-	PostCommandHook  func(cmd Command, itx *CommandInteraction)      // Function that runs after each command.
-	ComponentHandler func(itx *ComponentInteraction)                 // Function that runs for each unhandled component.
-	ModalHandler     func(itx *ModalInteraction)                     // Function that runs for each unhandled modal.
+	// Function that runs before each command. Return type signals whether to continue command execution (return with false to stop early).
+	//
+	// Deprecated: Use Middlewares (or BaseClient.Use) instead. Hook still works as the outermost global middleware.
+	PreCommandHook func(cmd Command, itx *CommandInteraction) bool
+	// Function that runs after each command.
+	//
+	// Deprecated: Use Middlewares (or BaseClient.Use) instead. Hook still works as the outermost global middleware.
+	PostCommandHook  func(cmd Command, itx *CommandInteraction)
+	ComponentHandler func(itx *ComponentInteraction) // Function that runs for each unhandled component.
+	ModalHandler     func(itx *ModalInteraction)     // Function that runs for each unhandled modal.
 
 	Logger                     *log.Logger // Optional custom logger. If tracing is enabled, this logger will be used for all internal messages. If none is provided, the default Stdout logger will be used instead.
 	Token                      string
 	DefaultInteractionContexts []InteractionContextType
 	RestOptions                RestOptions
+	AutoDefer                  AutoDeferOptions // Opt-in automatic deferral of interactions whose handlers don't respond in time.
+	Middlewares                []Middleware     // Global middleware for slash commands. See BaseClient.Use for details.
+
+	// Function that receives errors returned by handlers (and middleware), including recovered panics as *PanicError.
+	// By default, client traces error and replies with ephemeral "something went wrong" message if interaction wasn't answered yet.
+	ErrorHandler func(itx *Interaction, err error)
+
+	CooldownStore    CooldownStore                                           // Storage for command cooldowns (see Command.Cooldown). By default: in-memory store.
+	OnCooldown       func(itx *CommandInteraction, retryAfter time.Duration) // Function that runs when command is on cooldown. By default, client replies with ephemeral message.
+	OnMaxConcurrency func(itx *CommandInteraction)                           // Function that runs when command reached its concurrency limit. By default, client replies with ephemeral message.
+
+	ListenerStore ListenerStore // Storage for persistent listeners (see BaseClient.AwaitPersistentComponent). Persistent listeners are disabled without it.
+	Catalog       *Catalog      // Message catalogue used by Interaction.T and to fill in localizations of registered commands.
 }
 
 func NewBaseClient(opt BaseClientOptions) *BaseClient {
@@ -74,25 +112,45 @@ func NewBaseClient(opt BaseClientOptions) *BaseClient {
 	}
 
 	client := &BaseClient{
-		ApplicationID:      botUserID,
-		Rest:               NewRest(opt.RestOptions),
-		traceLogger:        traceLogger,
-		trace:              traceLogger.Writer() != io.Discard,
-		commands:           NewSharedMap[string, Command](),
-		commandContexts:    contexts,
-		staticComponents:   NewSharedMap[string, func(ComponentInteraction)](),
-		staticModals:       NewSharedMap[string, func(ModalInteraction)](),
-		preCommandHandler:  opt.PreCommandHook,
-		postCommandHandler: opt.PostCommandHook,
-		componentHandler:   opt.ComponentHandler,
-		modalHandler:       opt.ModalHandler,
-		queuedComponents:   NewSharedMap[string, *queuedComponent](),
-		queuedModals:       NewSharedMap[string, *queuedModal](),
+		ApplicationID:    botUserID,
+		Rest:             NewRest(opt.RestOptions),
+		traceLogger:      traceLogger,
+		trace:            traceLogger.Writer() != io.Discard,
+		commands:         NewSharedMap[string, Command](),
+		commandIDs:       NewSharedMap[Snowflake, string](),
+		commandContexts:  contexts,
+		staticComponents: NewSharedMap[string, func(ComponentInteraction)](),
+		staticModals:     NewSharedMap[string, func(ModalInteraction)](),
+		componentHandler: opt.ComponentHandler,
+		modalHandler:     opt.ModalHandler,
+		errorHandler:     opt.ErrorHandler,
+
+		cooldownStore:         opt.CooldownStore,
+		concurrency:           NewSharedMap[string, uint32](),
+		cooldownHandler:       opt.OnCooldown,
+		maxConcurrencyHandler: opt.OnMaxConcurrency,
+		listenerStore:         opt.ListenerStore,
+		listenerKinds:         NewSharedMap[string, listenerKind](),
+		catalog:               opt.Catalog,
+		queuedComponents:      NewSharedMap[string, *queuedComponent](),
+		queuedModals:          NewSharedMap[string, *queuedModal](),
+		componentRoutes:       &customIDRouter[func(itx *ComponentInteraction)]{},
+		modalRoutes:           &customIDRouter[func(itx *ModalInteraction)]{},
+		autoDefer:             opt.AutoDefer,
 		sweeper: interactionSweeper{
 			signal: make(chan struct{}, 1),
 		},
 	}
 
+	if opt.PreCommandHook != nil || opt.PostCommandHook != nil {
+		client.commandMiddlewares.add(hooksMiddleware(opt.PreCommandHook, opt.PostCommandHook))
+	}
+	client.commandMiddlewares.add(opt.Middlewares...)
+
+	if client.cooldownStore == nil {
+		client.cooldownStore = NewMemoryCooldownStore()
+	}
+
 	return client
 }
 
@@ -218,10 +276,11 @@ func (client *BaseClient) FetchMember(guildID Snowflake, memberID Snowflake) (Me
 // Returns all entitlements for a given app, active and expired.
 //
 // By default it will attempt to return all, existing entitlements - provide query filter to control this behavior.
+// Use Client.Entitlements to automatically walk through all pages.
 //
 // https://docs.discord.com/developers/resources/entitlement#list-entitlements
 func (client *BaseClient) FetchEntitlementsPage(queryFilter string) ([]Entitlement, error) {
-	if queryFilter[0] != '?' {
+	if queryFilter != "" && queryFilter[0] != '?' {
 		queryFilter = "?" + queryFilter
 	}
 
@@ -290,19 +349,29 @@ func (client *BaseClient) DeleteTestEntitlement(entitlementID Snowflake) error {
 	return err
 }
 
+// Bulk overwrites commands on Discord's side with locally registered ones (leave guildIDs empty to sync global commands).
+// Returned IDs & versions are stored back into registry. Use [BaseClient.SyncCommands] to only apply needed changes.
 func (client *BaseClient) SyncCommandsWithDiscord(guildIDs []Snowflake, whitelist []string, reverseMode bool) error {
 	commands := parseCommandsForDiscordAPI(client.commands, whitelist, reverseMode)
+	if errs := validateCommandSet(commands, false); len(errs) != 0 {
+		return errs
+	}
 
 	if len(guildIDs) == 0 {
-		_, err := client.Rest.Request(http.MethodPut, "/applications/"+client.ApplicationID.String()+"/commands", commands)
-		return err
+		guildIDs = []Snowflake{0}
 	}
 
 	for _, guildID := range guildIDs {
-		_, err := client.Rest.Request(http.MethodPut, "/applications/"+client.ApplicationID.String()+"/guilds/"+guildID.String()+"/commands", commands)
+		raw, err := client.Rest.Request(http.MethodPut, client.commandsEndpoint(guildID), commands)
 		if err != nil {
 			return err
 		}
+
+		var res []Command
+		if err := json.Unmarshal(raw, &res); err != nil {
+			return errors.New("failed to parse received data from discord")
+		}
+		client.storeCommandIDs(res)
 	}
 
 	client.tracef("Successfully synced command data with discord.")
@@ -310,21 +379,39 @@ func (client *BaseClient) SyncCommandsWithDiscord(guildIDs []Snowflake, whitelis
 }
 
 func (client *BaseClient) handleInteraction(itx CommandInteraction) (CommandInteraction, Command, bool) {
-	if len(itx.Data.Options) > 0 && itx.Data.Options[0].Type == SUB_COMMAND_OPTION_TYPE {
-		finalName := itx.Data.Name + "@" + itx.Data.Options[0].Name
-		subCommand, available := client.commands.Get(finalName)
-		if available {
-			if itx.Member != nil {
-				itx.Member.GuildID = itx.GuildID
-			}
+	if itx.Member != nil {
+		itx.Member.GuildID = itx.GuildID
+	}
 
-			itx.Data.Name, itx.Data.Options = finalName, itx.Data.Options[0].Options
-		}
-		return itx, subCommand, available
+	// Context menu (and other non slash) commands are stored under type prefixed keys and never have subcommands.
+	// Their name is kept as sent by Discord - use commandRegistryKey to get the key.
+	if itx.Data.Type != 0 && itx.Data.Type != CHAT_INPUT_COMMAND_TYPE {
+		command, available := client.commands.Get(commandRegistryKey(itx.Data.Type, itx.Data.Name))
+		return itx, command, available
 	}
 
-	if itx.Member != nil {
-		itx.Member.GuildID = itx.GuildID
+	if len(itx.Data.Options) > 0 {
+		switch option := itx.Data.Options[0]; option.Type {
+		case SUB_COMMAND_OPTION_TYPE:
+			finalName := itx.Data.Name + "@" + option.Name
+			subCommand, available := client.commands.Get(finalName)
+			if available {
+				itx.Data.Name, itx.Data.Options = finalName, option.Options
+			}
+			return itx, subCommand, available
+		case SUB_COMMAND_GROUP_OPTION_TYPE:
+			if len(option.Options) == 0 || option.Options[0].Type != SUB_COMMAND_OPTION_TYPE {
+				return itx, Command{}, false
+			}
+
+			nested := option.Options[0]
+			finalName := itx.Data.Name + "@" + option.Name + "@" + nested.Name
+			subCommand, available := client.commands.Get(finalName)
+			if available {
+				itx.Data.Name, itx.Data.Options = finalName, nested.Options
+			}
+			return itx, subCommand, available
+		}
 	}
 
 	command, available := client.commands.Get(itx.Data.Name)
@@ -339,7 +426,7 @@ func parseCommandsForDiscordAPI(commands *SharedMap[string, Command], whitelist
 
 	// First loop - prepare nested space for potential sub commands
 	for name, command := range commands.cache {
-		if strings.Contains(name, "@") {
+		if isSlashCommandKey(name) && strings.Contains(name, "@") {
 			continue
 		}
 
@@ -348,19 +435,19 @@ func parseCommandsForDiscordAPI(commands *SharedMap[string, Command], whitelist
 		tree[name] = group
 	}
 
-	// Second loop - assign commands
+	// Second loop - assign sub commands, groups & sub commands inside groups (keyed as "group@sub")
 	for name, command := range commands.cache {
-		if strings.Contains(name, "@") {
-			parts := strings.Split(name, "@")
-			group, ok := tree[parts[0]]
-			if !ok {
-				continue
-			}
+		rootName, path, found := strings.Cut(name, "@")
+		if !found || !isSlashCommandKey(name) {
+			continue
+		}
 
-			command.Type = CommandType(SUB_COMMAND_OPTION_TYPE)
-			group[parts[1]] = command
-			tree[parts[0]] = group
+		group, ok := tree[rootName]
+		if !ok {
+			continue
 		}
+
+		group[path] = command
 	}
 
 	commands.mu.RUnlock()
@@ -368,23 +455,35 @@ func parseCommandsForDiscordAPI(commands *SharedMap[string, Command], whitelist
 	// Use nested map to build final array with structs matching Discord API
 	for _, branch := range tree {
 		baseCommand := branch[ROOT_PLACEHOLDER]
+		baseCommand.ID = 0 // IDs are different in each scope (global & every guild) - Discord matches commands by name.
 
 		if len(branch) > 1 {
 			copiedOptions := make([]CommandOption, len(baseCommand.Options), len(baseCommand.Options)+len(branch)-1)
 			copy(copiedOptions, baseCommand.Options)
 			baseCommand.Options = copiedOptions
 
-			for key, subCommand := range branch {
-				if key == ROOT_PLACEHOLDER {
+			// Sorted keys keep options order stable between syncs.
+			keys := slices.Sorted(maps.Keys(branch))
+			for _, key := range keys {
+				if key == ROOT_PLACEHOLDER || strings.Contains(key, "@") {
 					continue
 				}
 
-				baseCommand.Options = append(baseCommand.Options, CommandOption{
-					Name:        subCommand.Name,
-					Description: subCommand.Description,
-					Type:        SUB_COMMAND_OPTION_TYPE,
-					Options:     subCommand.Options,
-				})
+				child := branch[key]
+				if !child.subCommandGroup {
+					baseCommand.Options = append(baseCommand.Options, subCommandOption(child, SUB_COMMAND_OPTION_TYPE))
+					continue
+				}
+
+				groupOption := subCommandOption(child, SUB_COMMAND_GROUP_OPTION_TYPE)
+				groupOption.Options = nil
+				for _, nestedKey := range keys {
+					if strings.HasPrefix(nestedKey, key+"@") {
+						groupOption.Options = append(groupOption.Options, subCommandOption(branch[nestedKey], SUB_COMMAND_OPTION_TYPE))
+					}
+				}
+
+				baseCommand.Options = append(baseCommand.Options, groupOption)
 			}
 		}
 
@@ -422,3 +521,14 @@ func parseCommandsForDiscordAPI(commands *SharedMap[string, Command], whitelist
 
 	return filtered
 }
+
+func subCommandOption(command Command, optionType OptionType) CommandOption {
+	return CommandOption{
+		NameLocalizations:        command.NameLocalizations,
+		DescriptionLocalizations: command.DescriptionLocalizations,
+		Name:                     command.Name,
+		Description:              command.Description,
+		Type:                     optionType,
+		Options:                  command.Options,
+	}
+}
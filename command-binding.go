@@ -0,0 +1,422 @@
+package tempest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	bindOptionTag       = "option"
+	bindDescriptionTag  = "description"
+	bindMinTag          = "min"
+	bindMaxTag          = "max"
+	bindMinLengthTag    = "min_length"
+	bindMaxLengthTag    = "max_length"
+	bindChoicesTag      = "choices"
+	bindChannelTypesTag = "channel_types"
+)
+
+var (
+	userType           = reflect.TypeFor[User]()
+	memberType         = reflect.TypeFor[Member]()
+	roleType           = reflect.TypeFor[Role]()
+	partialChannelType = reflect.TypeFor[PartialChannel]()
+	attachmentType     = reflect.TypeFor[Attachment]()
+	snowflakeType      = reflect.TypeFor[Snowflake]()
+)
+
+type boundField struct {
+	option   CommandOption
+	index    int
+	pointer  bool
+	elemType reflect.Type
+}
+
+// Parses options of command interaction into struct T. See CommandOptionsFrom for supported struct tags & field types.
+// It returns error when required option is missing or received value doesn't match field's type.
+func BindOptions[T any](itx *CommandInteraction) (T, error) {
+	var res T
+
+	fields, err := boundFieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return res, err
+	}
+
+	target := reflect.ValueOf(&res).Elem()
+	for _, field := range fields {
+		var option *CommandInteractionOption
+		for i := range itx.Data.Options {
+			if itx.Data.Options[i].Name == field.option.Name {
+				option = &itx.Data.Options[i]
+				break
+			}
+		}
+
+		if option == nil || option.Value == nil {
+			if field.option.Required {
+				return res, fmt.Errorf("missing required option \"%s\"", field.option.Name)
+			}
+			continue
+		}
+
+		value, err := bindValue(itx, field, option.Value)
+		if err != nil {
+			return res, fmt.Errorf("failed to bind option \"%s\": %w", field.option.Name, err)
+		}
+
+		dst := target.Field(field.index)
+		if field.pointer {
+			ptr := reflect.New(field.elemType)
+			ptr.Elem().Set(value)
+			dst.Set(ptr)
+		} else {
+			dst.Set(value)
+		}
+	}
+
+	return res, nil
+}
+
+// Generates command options from struct T, so command's definition and BindOptions parsing stay in sync.
+// Required options are always placed before optional ones (Discord's requirement).
+//
+// Supported struct tags:
+//
+//	option:"name,required,autocomplete" - option's name (by default: lowercase field name) and flags. Use "-" to skip field.
+//	description:"..."                   - option's description (by default: option's name).
+//	min:"1" & max:"10"                  - min/max value of integer & number options.
+//	min_length:"1" & max_length:"100"   - min/max length of string options.
+//	choices:"Red=red|Green=green"       - predefined choices, separated by "|". Use "value" alone when name and value are the same.
+//	channel_types:"0,2"                 - allowed channel types of channel options.
+//
+// Supported field types (and their option types):
+//
+//	string                            - STRING_OPTION_TYPE
+//	int, int8, int16, int32, int64    - INTEGER_OPTION_TYPE
+//	float32, float64                  - NUMBER_OPTION_TYPE
+//	bool                              - BOOLEAN_OPTION_TYPE
+//	User, Member                      - USER_OPTION_TYPE
+//	Role                              - ROLE_OPTION_TYPE
+//	PartialChannel                    - CHANNEL_OPTION_TYPE
+//	Attachment                        - ATTACHMENT_OPTION_TYPE
+//	Snowflake                         - MENTIONABLE_OPTION_TYPE
+//
+// Each type may also be used as pointer - pointer stays nil when user didn't provide that option.
+//
+// Example:
+//
+//	type BanOptions struct {
+//		Target User   `option:"target,required" description:"User to ban"`
+//		Days   int64  `option:"days" description:"Delete messages from last X days" min:"0" max:"7"`
+//		Reason string `option:"reason" max_length:"512"`
+//	}
+func CommandOptionsFrom[T any]() ([]CommandOption, error) {
+	fields, err := boundFieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]CommandOption, len(fields))
+	for i, field := range fields {
+		options[i] = field.option
+	}
+
+	slices.SortStableFunc(options, func(a, b CommandOption) int {
+		if a.Required == b.Required {
+			return 0
+		}
+		if a.Required {
+			return -1
+		}
+		return 1
+	})
+
+	return options, nil
+}
+
+func bindValue(itx *CommandInteraction, field boundField, raw any) (reflect.Value, error) {
+	elem := field.elemType
+
+	switch field.option.Type {
+	case STRING_OPTION_TYPE:
+		s, ok := raw.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected string, got %T", raw)
+		}
+		return reflect.ValueOf(s).Convert(elem), nil
+	case INTEGER_OPTION_TYPE:
+		n, ok := raw.(float64)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected integer, got %T", raw)
+		}
+
+		value := reflect.New(elem).Elem()
+		if n != math.Trunc(n) || value.OverflowInt(int64(n)) {
+			return reflect.Value{}, fmt.Errorf("value %v doesn't fit in %s", n, elem)
+		}
+		value.SetInt(int64(n))
+		return value, nil
+	case NUMBER_OPTION_TYPE:
+		n, ok := raw.(float64)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected number, got %T", raw)
+		}
+		return reflect.ValueOf(n).Convert(elem), nil
+	case BOOLEAN_OPTION_TYPE:
+		b, ok := raw.(bool)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected boolean, got %T", raw)
+		}
+		return reflect.ValueOf(b).Convert(elem), nil
+	}
+
+	s, ok := raw.(string)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("expected snowflake string, got %T", raw)
+	}
+
+	id, err := StringToSnowflake(s)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if elem == snowflakeType {
+		return reflect.ValueOf(id), nil
+	}
+
+	if itx.Data.Resolved == nil {
+		return reflect.Value{}, errors.New("interaction has no resolved data")
+	}
+
+	var (
+		resolved any
+		found    bool
+	)
+
+	switch elem {
+	case userType:
+		resolved, found = itx.Data.Resolved.Users[id]
+	case memberType:
+		var member Member
+		member, found = itx.Data.Resolved.Members[id]
+		if found {
+			user := itx.Data.Resolved.Users[id]
+			member.User = &user
+			member.GuildID = itx.GuildID
+		}
+		resolved = member
+	case roleType:
+		resolved, found = itx.Data.Resolved.Roles[id]
+	case partialChannelType:
+		resolved, found = itx.Data.Resolved.Channels[id]
+	case attachmentType:
+		resolved, found = itx.Data.Resolved.Attachments[id]
+	}
+
+	if !found {
+		return reflect.Value{}, fmt.Errorf("%s with ID %s is missing in resolved data", elem.Name(), id)
+	}
+
+	return reflect.ValueOf(resolved), nil
+}
+
+func boundFieldsOf(t reflect.Type) ([]boundField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("options can only be bound to struct, got %s", t)
+	}
+
+	fields := make([]boundField, 0, t.NumField())
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		tag, tagged := structField.Tag.Lookup(bindOptionTag)
+		if tag == "-" || !structField.IsExported() || (!tagged && structField.Anonymous) {
+			continue
+		}
+
+		field, err := boundFieldOf(structField, tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", t.Name(), structField.Name, err)
+		}
+
+		field.index = i
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func boundFieldOf(structField reflect.StructField, tag string) (boundField, error) {
+	field := boundField{elemType: structField.Type}
+	if field.elemType.Kind() == reflect.Pointer {
+		field.pointer = true
+		field.elemType = field.elemType.Elem()
+	}
+
+	parts := strings.Split(tag, ",")
+	option := CommandOption{Name: strings.TrimSpace(parts[0])}
+	if option.Name == "" {
+		option.Name = strings.ToLower(structField.Name)
+	}
+
+	for _, flag := range parts[1:] {
+		switch strings.TrimSpace(flag) {
+		case "required":
+			option.Required = true
+		case "autocomplete":
+			option.AutoComplete = true
+		default:
+			return field, fmt.Errorf("unknown option flag \"%s\"", flag)
+		}
+	}
+
+	option.Description = structField.Tag.Get(bindDescriptionTag)
+	if option.Description == "" {
+		option.Description = option.Name
+	}
+
+	switch field.elemType {
+	case userType, memberType:
+		option.Type = USER_OPTION_TYPE
+	case roleType:
+		option.Type = ROLE_OPTION_TYPE
+	case partialChannelType:
+		option.Type = CHANNEL_OPTION_TYPE
+	case attachmentType:
+		option.Type = ATTACHMENT_OPTION_TYPE
+	case snowflakeType:
+		option.Type = MENTIONABLE_OPTION_TYPE
+	default:
+		switch field.elemType.Kind() {
+		case reflect.String:
+			option.Type = STRING_OPTION_TYPE
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			option.Type = INTEGER_OPTION_TYPE
+		case reflect.Float32, reflect.Float64:
+			option.Type = NUMBER_OPTION_TYPE
+		case reflect.Bool:
+			option.Type = BOOLEAN_OPTION_TYPE
+		default:
+			return field, fmt.Errorf("unsupported field type %s", structField.Type)
+		}
+	}
+
+	if err := parseOptionLimits(&option, structField.Tag); err != nil {
+		return field, err
+	}
+
+	field.option = option
+	return field, nil
+}
+
+func parseOptionLimits(option *CommandOption, tag reflect.StructTag) error {
+	for _, name := range []string{bindMinTag, bindMaxTag} {
+		raw, ok := tag.Lookup(name)
+		if !ok {
+			continue
+		}
+
+		if option.Type != INTEGER_OPTION_TYPE && option.Type != NUMBER_OPTION_TYPE {
+			return fmt.Errorf("\"%s\" tag is only valid for integer & number options", name)
+		}
+
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid \"%s\" tag: %w", name, err)
+		}
+
+		if name == bindMinTag {
+			option.MinValue = &n
+		} else {
+			option.MaxValue = &n
+		}
+	}
+
+	for _, name := range []string{bindMinLengthTag, bindMaxLengthTag} {
+		raw, ok := tag.Lookup(name)
+		if !ok {
+			continue
+		}
+
+		if option.Type != STRING_OPTION_TYPE {
+			return fmt.Errorf("\"%s\" tag is only valid for string options", name)
+		}
+
+		n, err := strconv.ParseUint(raw, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid \"%s\" tag: %w", name, err)
+		}
+
+		if name == bindMinLengthTag {
+			option.MinLength = uint16(n)
+		} else {
+			option.MaxLength = uint16(n)
+		}
+	}
+
+	if raw, ok := tag.Lookup(bindChoicesTag); ok {
+		choices, err := parseOptionChoices(option.Type, raw)
+		if err != nil {
+			return err
+		}
+		option.Choices = choices
+	}
+
+	if raw, ok := tag.Lookup(bindChannelTypesTag); ok {
+		if option.Type != CHANNEL_OPTION_TYPE {
+			return fmt.Errorf("\"%s\" tag is only valid for channel options", bindChannelTypesTag)
+		}
+
+		for _, part := range strings.Split(raw, ",") {
+			n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 8)
+			if err != nil {
+				return fmt.Errorf("invalid \"%s\" tag: %w", bindChannelTypesTag, err)
+			}
+			option.ChannelTypes = append(option.ChannelTypes, ChannelType(n))
+		}
+	}
+
+	return nil
+}
+
+func parseOptionChoices(optionType OptionType, raw string) ([]CommandOptionChoice, error) {
+	entries := strings.Split(raw, "|")
+	choices := make([]CommandOptionChoice, 0, len(entries))
+
+	for _, entry := range entries {
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			value = name
+		}
+
+		choice := CommandOptionChoice{Name: strings.TrimSpace(name)}
+		value = strings.TrimSpace(value)
+
+		switch optionType {
+		case STRING_OPTION_TYPE:
+			choice.Value = value
+		case INTEGER_OPTION_TYPE:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer choice \"%s\": %w", value, err)
+			}
+			choice.Value = n
+		case NUMBER_OPTION_TYPE:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number choice \"%s\": %w", value, err)
+			}
+			choice.Value = n
+		default:
+			return nil, fmt.Errorf("\"%s\" tag is only valid for string, integer & number options", bindChoicesTag)
+		}
+
+		choices = append(choices, choice)
+	}
+
+	return choices, nil
+}
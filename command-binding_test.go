@@ -0,0 +1,77 @@
+package tempest
+
+import (
+	"testing"
+)
+
+type testBanOptions struct {
+	Reason string  `option:"reason" max_length:"512"`
+	Target User    `option:"target,required" description:"User to ban"`
+	Days   *int64  `option:"days" min:"0" max:"7"`
+	Mode   string  `option:"mode" choices:"Soft=soft|hard"`
+	Ratio  float64 `option:"ratio"`
+	Notify bool    `option:"notify"`
+	Hidden string  `option:"-"`
+}
+
+func TestCommandOptionsFrom(t *testing.T) {
+	options, err := CommandOptionsFrom[testBanOptions]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(options) != 6 {
+		t.Fatalf("expected 6 options, got %d", len(options))
+	}
+
+	if options[0].Name != "target" || !options[0].Required || options[0].Type != USER_OPTION_TYPE {
+		t.Errorf("expected required user option to go first, got %+v", options[0])
+	}
+
+	days := options[2]
+	if days.Type != INTEGER_OPTION_TYPE || days.MinValue == nil || *days.MinValue != 0 || days.MaxValue == nil || *days.MaxValue != 7 {
+		t.Errorf("unexpected days option: %+v", days)
+	}
+
+	mode := options[3]
+	if len(mode.Choices) != 2 || mode.Choices[0].Name != "Soft" || mode.Choices[0].Value != "soft" || mode.Choices[1].Name != "hard" {
+		t.Errorf("unexpected mode choices: %+v", mode.Choices)
+	}
+
+	if _, err := CommandOptionsFrom[struct {
+		Bad []string `option:"bad"`
+	}](); err == nil {
+		t.Error("expected error for unsupported field type")
+	}
+}
+
+func TestBindOptions(t *testing.T) {
+	itx := &CommandInteraction{
+		Interaction: &Interaction{},
+		Data: CommandInteractionData{
+			Options: []CommandInteractionOption{
+				{Name: "target", Type: USER_OPTION_TYPE, Value: "123"},
+				{Name: "days", Type: INTEGER_OPTION_TYPE, Value: float64(3)},
+				{Name: "ratio", Type: NUMBER_OPTION_TYPE, Value: 0.5},
+				{Name: "notify", Type: BOOLEAN_OPTION_TYPE, Value: true},
+			},
+			Resolved: &InteractionDataResolved{
+				Users: map[Snowflake]User{123: {ID: 123, Username: "tester"}},
+			},
+		},
+	}
+
+	opt, err := BindOptions[testBanOptions](itx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opt.Target.Username != "tester" || opt.Days == nil || *opt.Days != 3 || opt.Ratio != 0.5 || !opt.Notify || opt.Reason != "" {
+		t.Errorf("unexpected bound options: %+v", opt)
+	}
+
+	itx.Data.Options = itx.Data.Options[1:]
+	if _, err := BindOptions[testBanOptions](itx); err == nil {
+		t.Error("expected error for missing required option")
+	}
+}
@@ -0,0 +1,399 @@
+package tempest
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Describes single problem found in command definition.
+// Path points at invalid element, e.g. "config.logging.channel.target" or "ban.reason.choices[2]".
+type CommandValidationError struct {
+	Path    string
+	Message string
+}
+
+func (err CommandValidationError) Error() string {
+	return err.Path + ": " + err.Message
+}
+
+// All problems found in command definitions. Returned by [BaseClient.ValidateCommands] and command registration methods.
+type CommandValidationErrors []CommandValidationError
+
+func (errs CommandValidationErrors) Error() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(len(errs)) + " invalid command definition(s):")
+	for _, err := range errs {
+		sb.WriteString("\n  - " + err.Error())
+	}
+	return sb.String()
+}
+
+// https://docs.discord.com/developers/interactions/application-commands#application-command-object-application-command-naming
+var chatInputNameRegex = regexp.MustCompile(`^[-_'\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+const (
+	maxCommandOptions        = 25
+	maxCommandChoices        = 25
+	maxChatInputCommands     = 100 // Per scope - global or single guild.
+	maxContextMenuCommands   = 15  // Per scope & per type (user or message).
+	maxCommandDescriptionLen = 100
+	maxChoiceNameLen         = 100
+	maxChoiceStringValueLen  = 100
+	maxOptionLength          = 6000
+)
+
+// Checks all registered commands (including subcommands & groups) against Discord's rules,
+// so invalid definitions are caught locally instead of failing whole sync with bad request response.
+// Command caps are counted separately for global scope and each guild (based on Command.GuildID).
+//
+// It returns nil or [CommandValidationErrors] with every problem found.
+func (client *BaseClient) ValidateCommands() error {
+	commands := parseCommandsForDiscordAPI(client.commands, nil, false)
+	if errs := validateCommandSet(commands, true); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validates list of commands. When scoped is false, all commands are treated as part of the same scope (like during sync to specific guilds).
+func validateCommandSet(commands []Command, scoped bool) CommandValidationErrors {
+	type scopeKey struct {
+		guildID Snowflake
+		kind    CommandType
+	}
+
+	var errs CommandValidationErrors
+	counts := make(map[scopeKey]int)
+	levels := make(map[scopeKey][]nameEntry)
+
+	for _, cmd := range commands {
+		errs = validateCommand(errs, cmd)
+
+		key := scopeKey{kind: cmd.Type}
+		if key.kind == 0 {
+			key.kind = CHAT_INPUT_COMMAND_TYPE
+		}
+		if scoped {
+			key.guildID = cmd.GuildID
+		}
+
+		counts[key]++
+		levels[key] = append(levels[key], nameEntry{name: cmd.Name, localizations: cmd.NameLocalizations})
+	}
+
+	for key, count := range counts {
+		limit := maxContextMenuCommands
+		switch key.kind {
+		case CHAT_INPUT_COMMAND_TYPE:
+			limit = maxChatInputCommands
+		case PRIMARY_ENTRY_POINT_COMMAND_TYPE:
+			limit = 1
+		}
+
+		if count > limit {
+			scope := "global"
+			if key.guildID != 0 {
+				scope = "guild " + key.guildID.String()
+			}
+			errs = append(errs, CommandValidationError{Path: scope, Message: "too many " + commandTypeName(key.kind) + " commands (" + strconv.Itoa(count) + "/" + strconv.Itoa(limit) + ")"})
+		}
+
+		errs = checkDuplicateNames(errs, "", levels[key])
+	}
+
+	return errs
+}
+
+// Validates single root command, together with its options tree.
+func validateCommand(errs CommandValidationErrors, cmd Command) CommandValidationErrors {
+	path := cmd.Name
+	if path == "" {
+		path = "<unnamed command>"
+	}
+
+	switch cmd.Type {
+	case 0, CHAT_INPUT_COMMAND_TYPE:
+		errs = checkChatInputName(errs, path, cmd.Name, cmd.NameLocalizations)
+		errs = checkDescription(errs, path, cmd.Description, cmd.DescriptionLocalizations)
+		errs = validateOptions(errs, path, cmd.Options, 0)
+	case USER_COMMAND_TYPE, MESSAGE_COMMAND_TYPE:
+		if n := utf8.RuneCountInString(cmd.Name); n < 1 || n > 32 {
+			errs = append(errs, CommandValidationError{Path: path, Message: "name must be between 1 and 32 characters"})
+		}
+
+		for lang, name := range cmd.NameLocalizations {
+			if n := utf8.RuneCountInString(name); n < 1 || n > 32 {
+				errs = append(errs, CommandValidationError{Path: path, Message: "localized name (" + string(lang) + ") must be between 1 and 32 characters"})
+			}
+		}
+
+		if cmd.Description != "" || len(cmd.DescriptionLocalizations) != 0 {
+			errs = append(errs, CommandValidationError{Path: path, Message: commandTypeName(cmd.Type) + " commands cannot have description"})
+		}
+
+		if len(cmd.Options) != 0 {
+			errs = append(errs, CommandValidationError{Path: path, Message: commandTypeName(cmd.Type) + " commands cannot have options"})
+		}
+	}
+
+	return errs
+}
+
+// Validates subcommand (or group) as it'll be serialized into parent's options.
+func validateSubCommand(parentCommandName string, subCommand Command, optionType OptionType) CommandValidationErrors {
+	var parent OptionType = 0
+	if strings.Contains(parentCommandName, "@") {
+		parent = SUB_COMMAND_GROUP_OPTION_TYPE
+	}
+
+	option := subCommandOption(subCommand, optionType)
+	return validateOptions(nil, strings.ReplaceAll(parentCommandName, "@", "."), []CommandOption{option}, parent)
+}
+
+// Validates one level of options. Parent is type of option holding them (0 for root command).
+func validateOptions(errs CommandValidationErrors, path string, options []CommandOption, parent OptionType) CommandValidationErrors {
+	if len(options) > maxCommandOptions {
+		errs = append(errs, CommandValidationError{Path: path, Message: "too many options (" + strconv.Itoa(len(options)) + "/" + strconv.Itoa(maxCommandOptions) + ")"})
+	}
+
+	entries := make([]nameEntry, 0, len(options))
+	nested, regular, optionalSeen := 0, 0, false
+
+	for i, option := range options {
+		optionPath := path + "." + option.Name
+		if option.Name == "" {
+			optionPath = path + ".options[" + strconv.Itoa(i) + "]"
+		}
+
+		entries = append(entries, nameEntry{name: option.Name, localizations: option.NameLocalizations})
+		errs = checkChatInputName(errs, optionPath, option.Name, option.NameLocalizations)
+		errs = checkDescription(errs, optionPath, option.Description, option.DescriptionLocalizations)
+
+		switch option.Type {
+		case SUB_COMMAND_OPTION_TYPE, SUB_COMMAND_GROUP_OPTION_TYPE:
+			nested++
+			if option.Type == SUB_COMMAND_GROUP_OPTION_TYPE && parent != 0 {
+				errs = append(errs, CommandValidationError{Path: optionPath, Message: "subcommand groups can only be placed directly in root command"})
+			}
+
+			if option.Type == SUB_COMMAND_OPTION_TYPE && parent == SUB_COMMAND_OPTION_TYPE {
+				errs = append(errs, CommandValidationError{Path: optionPath, Message: "subcommands cannot be nested inside other subcommands"})
+			}
+
+			errs = validateOptions(errs, optionPath, option.Options, option.Type)
+			continue
+		case 0:
+			errs = append(errs, CommandValidationError{Path: optionPath, Message: "missing option type"})
+		default:
+			if option.Type > ATTACHMENT_OPTION_TYPE {
+				errs = append(errs, CommandValidationError{Path: optionPath, Message: "unknown option type " + strconv.Itoa(int(option.Type))})
+			}
+		}
+
+		regular++
+		if parent == SUB_COMMAND_GROUP_OPTION_TYPE {
+			errs = append(errs, CommandValidationError{Path: optionPath, Message: "subcommand groups can only contain subcommands"})
+		}
+
+		if option.Required && optionalSeen {
+			errs = append(errs, CommandValidationError{Path: optionPath, Message: "required options must be placed before optional ones"})
+		}
+		optionalSeen = optionalSeen || !option.Required
+
+		if len(option.Options) != 0 {
+			errs = append(errs, CommandValidationError{Path: optionPath, Message: "only subcommands and subcommand groups can have nested options"})
+		}
+
+		errs = validateOptionValues(errs, optionPath, option)
+	}
+
+	if nested != 0 && regular != 0 {
+		errs = append(errs, CommandValidationError{Path: path, Message: "cannot mix subcommands (or groups) with regular options on the same level"})
+	}
+
+	return checkDuplicateNames(errs, path, entries)
+}
+
+// Validates choices, auto complete and value constraints of single (non subcommand) option.
+func validateOptionValues(errs CommandValidationErrors, path string, option CommandOption) CommandValidationErrors {
+	numeric := option.Type == INTEGER_OPTION_TYPE || option.Type == NUMBER_OPTION_TYPE
+	supportsChoices := numeric || option.Type == STRING_OPTION_TYPE
+
+	if len(option.Choices) != 0 && option.AutoComplete {
+		errs = append(errs, CommandValidationError{Path: path, Message: "choices and auto complete are mutually exclusive"})
+	}
+
+	if option.AutoComplete && !supportsChoices {
+		errs = append(errs, CommandValidationError{Path: path, Message: "auto complete is only available for string, integer and number options"})
+	}
+
+	if len(option.Choices) != 0 && !supportsChoices {
+		errs = append(errs, CommandValidationError{Path: path, Message: "choices are only available for string, integer and number options"})
+	}
+
+	if len(option.Choices) > maxCommandChoices {
+		errs = append(errs, CommandValidationError{Path: path, Message: "too many choices (" + strconv.Itoa(len(option.Choices)) + "/" + strconv.Itoa(maxCommandChoices) + ")"})
+	}
+
+	if supportsChoices {
+		for i, choice := range option.Choices {
+			errs = validateChoice(errs, path+".choices["+strconv.Itoa(i)+"]", option.Type, choice)
+		}
+	}
+
+	if (option.MinValue != nil || option.MaxValue != nil) && !numeric {
+		errs = append(errs, CommandValidationError{Path: path, Message: "min/max value is only available for integer and number options"})
+	}
+
+	if option.MinValue != nil && option.MaxValue != nil && *option.MinValue > *option.MaxValue {
+		errs = append(errs, CommandValidationError{Path: path, Message: "min value cannot be greater than max value"})
+	}
+
+	if option.Type == INTEGER_OPTION_TYPE {
+		for _, bound := range []*float64{option.MinValue, option.MaxValue} {
+			if bound != nil && *bound != float64(int64(*bound)) {
+				errs = append(errs, CommandValidationError{Path: path, Message: "min/max value of integer option must be a whole number"})
+				break
+			}
+		}
+	}
+
+	if (option.MinLength != 0 || option.MaxLength != 0) && option.Type != STRING_OPTION_TYPE {
+		errs = append(errs, CommandValidationError{Path: path, Message: "min/max length is only available for string options"})
+	}
+
+	if option.MinLength > maxOptionLength || option.MaxLength > maxOptionLength {
+		errs = append(errs, CommandValidationError{Path: path, Message: "min/max length cannot exceed " + strconv.Itoa(maxOptionLength)})
+	}
+
+	if option.MaxLength != 0 && option.MinLength > option.MaxLength {
+		errs = append(errs, CommandValidationError{Path: path, Message: "min length cannot be greater than max length"})
+	}
+
+	if len(option.ChannelTypes) != 0 && option.Type != CHANNEL_OPTION_TYPE {
+		errs = append(errs, CommandValidationError{Path: path, Message: "channel types are only available for channel options"})
+	}
+
+	return errs
+}
+
+func validateChoice(errs CommandValidationErrors, path string, optionType OptionType, choice CommandOptionChoice) CommandValidationErrors {
+	if n := utf8.RuneCountInString(choice.Name); n < 1 || n > maxChoiceNameLen {
+		errs = append(errs, CommandValidationError{Path: path, Message: "choice name must be between 1 and 100 characters"})
+	}
+
+	for lang, name := range choice.NameLocalizations {
+		if n := utf8.RuneCountInString(name); n < 1 || n > maxChoiceNameLen {
+			errs = append(errs, CommandValidationError{Path: path, Message: "localized choice name (" + string(lang) + ") must be between 1 and 100 characters"})
+		}
+	}
+
+	switch value := choice.Value.(type) {
+	case string:
+		if optionType != STRING_OPTION_TYPE {
+			errs = append(errs, CommandValidationError{Path: path, Message: "choice value must be a number"})
+		} else if n := utf8.RuneCountInString(value); n < 1 || n > maxChoiceStringValueLen {
+			errs = append(errs, CommandValidationError{Path: path, Message: "choice value must be between 1 and 100 characters"})
+		}
+	case float64, float32:
+		if optionType == STRING_OPTION_TYPE {
+			errs = append(errs, CommandValidationError{Path: path, Message: "choice value must be a string"})
+		} else if f, _ := value.(float64); optionType == INTEGER_OPTION_TYPE && f != float64(int64(f)) {
+			errs = append(errs, CommandValidationError{Path: path, Message: "choice value of integer option must be a whole number"})
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if optionType == STRING_OPTION_TYPE {
+			errs = append(errs, CommandValidationError{Path: path, Message: "choice value must be a string"})
+		}
+	default:
+		errs = append(errs, CommandValidationError{Path: path, Message: "choice value must be a string or a number"})
+	}
+
+	return errs
+}
+
+func checkChatInputName(errs CommandValidationErrors, path string, name string, localizations map[Language]string) CommandValidationErrors {
+	if !chatInputNameRegex.MatchString(name) {
+		errs = append(errs, CommandValidationError{Path: path, Message: "name must be 1-32 characters long and contain only letters, numbers, \"-\", \"_\" or \"'\""})
+	} else if strings.ToLower(name) != name {
+		errs = append(errs, CommandValidationError{Path: path, Message: "name must be lowercase"})
+	}
+
+	for lang, localized := range localizations {
+		if !chatInputNameRegex.MatchString(localized) {
+			errs = append(errs, CommandValidationError{Path: path, Message: "localized name (" + string(lang) + ") must be 1-32 characters long and contain only letters, numbers, \"-\", \"_\" or \"'\""})
+		} else if strings.ToLower(localized) != localized {
+			errs = append(errs, CommandValidationError{Path: path, Message: "localized name (" + string(lang) + ") must be lowercase"})
+		}
+	}
+
+	return errs
+}
+
+func checkDescription(errs CommandValidationErrors, path string, description string, localizations map[Language]string) CommandValidationErrors {
+	if n := utf8.RuneCountInString(description); n < 1 || n > maxCommandDescriptionLen {
+		errs = append(errs, CommandValidationError{Path: path, Message: "description must be between 1 and 100 characters"})
+	}
+
+	for lang, localized := range localizations {
+		if n := utf8.RuneCountInString(localized); n < 1 || n > maxCommandDescriptionLen {
+			errs = append(errs, CommandValidationError{Path: path, Message: "localized description (" + string(lang) + ") must be between 1 and 100 characters"})
+		}
+	}
+
+	return errs
+}
+
+type nameEntry struct {
+	name          string
+	localizations map[Language]string
+}
+
+// Reports names (and localized names, per language) used more than once on the same level.
+func checkDuplicateNames(errs CommandValidationErrors, path string, entries []nameEntry) CommandValidationErrors {
+	seen := make(map[string]struct{}, len(entries))
+	seenLocalized := make(map[Language]map[string]struct{})
+
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for _, entry := range entries {
+		if entry.name != "" {
+			if _, exists := seen[entry.name]; exists {
+				errs = append(errs, CommandValidationError{Path: prefix + entry.name, Message: "name is already used on the same level"})
+			}
+			seen[entry.name] = struct{}{}
+		}
+
+		for lang, localized := range entry.localizations {
+			names, ok := seenLocalized[lang]
+			if !ok {
+				names = make(map[string]struct{})
+				seenLocalized[lang] = names
+			}
+
+			if _, exists := names[localized]; exists {
+				errs = append(errs, CommandValidationError{Path: prefix + entry.name, Message: "localized name (" + string(lang) + ") \"" + localized + "\" is already used on the same level"})
+			}
+			names[localized] = struct{}{}
+		}
+	}
+
+	return errs
+}
+
+func commandTypeName(kind CommandType) string {
+	switch kind {
+	case USER_COMMAND_TYPE:
+		return "user"
+	case MESSAGE_COMMAND_TYPE:
+		return "message"
+	case PRIMARY_ENTRY_POINT_COMMAND_TYPE:
+		return "entry point"
+	}
+	return "slash"
+}
@@ -0,0 +1,44 @@
+package tempest
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateCommands(t *testing.T) {
+	client := &BaseClient{commands: NewSharedMap[string, Command]()}
+	maxValue := 1.5
+
+	client.commands.Set("Ban", Command{
+		Name:        "Ban",
+		Description: "Bans member.",
+		Options: []CommandOption{
+			{Name: "reason", Description: "Reason.", Type: STRING_OPTION_TYPE, AutoComplete: true, Choices: []CommandOptionChoice{{Name: "Spam", Value: "spam"}}},
+			{Name: "target", Description: "Target.", Type: USER_OPTION_TYPE, Required: true, MaxValue: &maxValue},
+			{Name: "days", Description: "Days.", Type: INTEGER_OPTION_TYPE, NameLocalizations: map[Language]string{POLISH_LANGUAGE: "powod"}},
+			{Name: "note", Description: "Note.", Type: STRING_OPTION_TYPE, NameLocalizations: map[Language]string{POLISH_LANGUAGE: "powod"}},
+		},
+	})
+	client.commands.Set("avatar", Command{Name: "avatar", Description: "Shows avatar.", Type: USER_COMMAND_TYPE})
+
+	err := client.ValidateCommands()
+	var errs CommandValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected CommandValidationErrors, got %v", err)
+	}
+
+	expected := map[string]int{"Ban": 1, "Ban.reason": 1, "Ban.target": 2, "Ban.note": 1, "avatar": 1}
+	for _, e := range errs {
+		expected[e.Path]--
+	}
+
+	for path, left := range expected {
+		if left != 0 {
+			t.Errorf("unexpected number of errors for %q (off by %d), all errors: %v", path, left, err)
+		}
+	}
+
+	if err := client.RegisterSubCommand(Command{Name: "bad", Description: "Bad."}, "avatar"); err == nil {
+		t.Error("expected error when registering subcommand under context menu command")
+	}
+}
@@ -1,5 +1,7 @@
 package tempest
 
+import "context"
+
 // https://docs.discord.com/developers/interactions/application-commands#application-command-object-application-command-types
 type CommandType uint8
 
@@ -14,8 +16,8 @@ const (
 type OptionType uint8
 
 const (
-	SUB_COMMAND_OPTION_TYPE       OptionType = iota + 1
-	SUB_COMMAND_GROUP_OPTION_TYPE            // NOT OFFICIALLY SUPPORTED BY LIBRARY
+	SUB_COMMAND_OPTION_TYPE OptionType = iota + 1
+	SUB_COMMAND_GROUP_OPTION_TYPE
 	STRING_OPTION_TYPE
 	INTEGER_OPTION_TYPE
 	BOOLEAN_OPTION_TYPE
@@ -82,8 +84,23 @@ const (
 // https://docs.discord.com/developers/interactions/application-commands#application-command-object-application-command-structure
 type Command struct {
 	SlashCommandHandler func(itx *CommandInteraction) `json:"-"` // Custom handler for slash command interactions. It's a Tempest specific field. It receives pointer to CommandInteraction as it's being used with pre & post client hooks.
+	// Variant of SlashCommandHandler that returns error, which is passed to client's error handler. Set only one of them. It's a Tempest specific field.
+	SlashCommandHandlerE func(itx *CommandInteraction) error `json:"-"`
+
+	// Handler for user (context menu) commands. It receives resolved target user and their member data (nil outside guilds). See BaseClient.RegisterUserCommand. It's a Tempest specific field.
+	UserCommandHandler func(itx *CommandInteraction, target User, member *Member) error `json:"-"`
+	// Handler for message (context menu) commands. It receives resolved target message. See BaseClient.RegisterMessageCommand. It's a Tempest specific field.
+	MessageCommandHandler func(itx *CommandInteraction, target Message) error `json:"-"`
+
+	// Variant of AutoCompleteHandler that returns error and receives context that expires once Discord stops waiting for choices (see AUTO_COMPLETE_DEADLINE).
+	// Set only one of them. It's a Tempest specific field.
+	AutoCompleteHandlerE func(ctx context.Context, itx CommandInteraction) ([]CommandOptionChoice, error) `json:"-"`
 
 	AutoCompleteHandler      func(itx CommandInteraction) []CommandOptionChoice `json:"-"` // Custom handler for auto complete interactions. It's a Tempest specific field.
+	AutoDefer                AutoDeferMode                                      `json:"-"` // Overrides client's auto defer settings for this command. It's a Tempest specific field.
+	Cooldown                 Cooldown                                           `json:"-"` // Limits how often command can be used. It's checked after all middleware, right before handler. It's a Tempest specific field.
+	MaxConcurrency           MaxConcurrency                                     `json:"-"` // Limits how many invocations of command can run at the same time. It's a Tempest specific field.
+	Middlewares              []Middleware                                       `json:"-"` // Middleware that wraps this command (and all its subcommands, when set on parent or group). It's a Tempest specific field.
 	DescriptionLocalizations map[Language]string                                `json:"description_localizations,omitzero"`
 	NameLocalizations        map[Language]string                                `json:"name_localizations,omitzero"`
 	Description              string                                             `json:"description"`
@@ -92,13 +109,27 @@ type Command struct {
 	IntegrationTypes         []ApplicationIntegrationType                       `json:"integration_types,omitzero"`
 	Contexts                 []InteractionContextType                           `json:"contexts,omitzero"` // Interaction context(s) where the command can be used, only for globally-scoped commands. By default, all interaction context types included for new commands.
 	GuildID                  Snowflake                                          `json:"guild_id,omitempty"`
-	ID                       Snowflake                                          `json:"-"`                                           // It's not needed on app side to work.
+	ID                       Snowflake                                          `json:"id,omitempty"`                                // Filled after syncing commands with Discord. Needed for command mentions and permission edits.
 	RequiredPermissions      PermissionFlags                                    `json:"default_member_permissions,string,omitempty"` // Set of permissions represented as a bit set that are required from user/member to use command. Set it to 0 to make command unavailable for regular members (guild administrators still can use it).
 	Version                  Snowflake                                          `json:"version,omitempty"`                           // Autoincrementing version identifier updated during substantial record changes.
 	ApplicationID            Snowflake                                          `json:"application_id"`
 	NSFW                     bool                                               `json:"nsfw"` // https://docs.discord.com/developers/interactions/application-commands#agerestricted-commands
 	Handler                  CommandHandlerType                                 `json:"handler,omitempty"`
 	Type                     CommandType                                        `json:"type,omitempty"`
+
+	subCommandGroup bool // Marks registry entries created with RegisterSubCommandGroup (groups only exist as options of root command on Discord's side).
+}
+
+// Renders clickable mention of command, e.g. "</ping:123>". Pass subcommand group and/or subcommand names to mention nested command.
+// Command needs to have ID (it's filled in after syncing or fetching commands).
+//
+// https://docs.discord.com/developers/reference#message-formatting
+func (cmd Command) Mention(subCommandNames ...string) string {
+	name := cmd.Name
+	for _, subName := range subCommandNames {
+		name += " " + subName
+	}
+	return "</" + name + ":" + cmd.ID.String() + ">"
 }
 
 // https://docs.discord.com/developers/interactions/application-commands#application-command-object-application-command-option-structure
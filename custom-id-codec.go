@@ -0,0 +1,259 @@
+package tempest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCustomIDMalformed = errors.New("custom ID is malformed")                                   // Custom ID isn't in format produced by codec.
+	ErrCustomIDTampered  = errors.New("custom ID signature is invalid")                           // Custom ID was modified or signed with different secret.
+	ErrCustomIDExpired   = errors.New("custom ID has expired")                                    // Custom ID was valid, but its time to live has passed.
+	ErrCustomIDTooLong   = errors.New("encoded custom ID exceeds Discord's 100 characters limit") // State (with prefix) is too large to fit in custom ID.
+)
+
+const (
+	customIDCodecVersion   byte = 1
+	customIDSignatureBytes      = 12 // Truncated HMAC-SHA256 (96 bits) - plenty for IDs that are only valid for single app.
+	maxCustomIDLength           = 100
+)
+
+// Packs small structs into signed, compact custom IDs (like "page:AQo...") so components & modals can carry state
+// across app restarts without keeping anything in memory. Signature covers prefix, expiry and state, so users can't
+// forge or move values between different components.
+//
+// Supported state fields: bool, all integer types (including Snowflake & enums), floats, strings and nested structs.
+// Fields are encoded in declaration order, without names - adding, removing or reordering fields invalidates existing IDs.
+//
+// Use one codec (with the same secret) across all app instances.
+type CustomIDCodec struct {
+	now    func() time.Time
+	secret []byte
+}
+
+// Creates codec with given secret. Secret needs at least 16 bytes and should never be shared with anyone.
+func NewCustomIDCodec(secret []byte) *CustomIDCodec {
+	if len(secret) < 16 {
+		panic("custom ID codec secret must have at least 16 bytes")
+	}
+
+	return &CustomIDCodec{
+		now:    time.Now,
+		secret: append([]byte(nil), secret...),
+	}
+}
+
+// Encodes state into custom ID with given prefix (prefix can't be empty, but can contain ":" separated segments).
+// Use ttl = 0 for custom IDs that never expire.
+func (codec *CustomIDCodec) Encode(prefix string, state any, ttl time.Duration) (string, error) {
+	if prefix == "" {
+		return "", errors.New("custom ID prefix cannot be empty")
+	}
+
+	var expiresAt uint64
+	if ttl > 0 {
+		expiresAt = uint64(codec.now().Add(ttl).Unix())
+	}
+
+	payload := make([]byte, 0, 32)
+	payload = append(payload, customIDCodecVersion)
+	payload = binary.AppendUvarint(payload, expiresAt)
+
+	payload, err := appendCustomIDValue(payload, reflect.ValueOf(state))
+	if err != nil {
+		return "", err
+	}
+
+	payload = append(payload, codec.sign(prefix, payload)...)
+	customID := prefix + ROUTE_SEGMENT_SEPARATOR + base64.RawURLEncoding.EncodeToString(payload)
+	if len(customID) > maxCustomIDLength {
+		return "", ErrCustomIDTooLong
+	}
+
+	return customID, nil
+}
+
+// Verifies custom ID and decodes its state into dst (pointer to the same type that was encoded).
+// Returns one of ErrCustomIDMalformed, ErrCustomIDTampered or ErrCustomIDExpired when custom ID can't be trusted.
+func (codec *CustomIDCodec) Decode(customID string, dst any) error {
+	idx := strings.LastIndex(customID, ROUTE_SEGMENT_SEPARATOR)
+	if idx <= 0 {
+		return ErrCustomIDMalformed
+	}
+
+	prefix := customID[:idx]
+	payload, err := base64.RawURLEncoding.DecodeString(customID[idx+1:])
+	if err != nil || len(payload) < 1+customIDSignatureBytes {
+		return ErrCustomIDMalformed
+	}
+
+	signature := payload[len(payload)-customIDSignatureBytes:]
+	payload = payload[:len(payload)-customIDSignatureBytes]
+	if !hmac.Equal(signature, codec.sign(prefix, payload)) {
+		return ErrCustomIDTampered
+	}
+
+	if payload[0] != customIDCodecVersion {
+		return ErrCustomIDMalformed
+	}
+
+	expiresAt, n := binary.Uvarint(payload[1:])
+	if n <= 0 {
+		return ErrCustomIDMalformed
+	}
+
+	if expiresAt != 0 && codec.now().Unix() > int64(expiresAt) {
+		return ErrCustomIDExpired
+	}
+
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return errors.New("custom ID state destination must be non-nil pointer")
+	}
+
+	rest, err := readCustomIDValue(payload[1+n:], target.Elem())
+	if err != nil {
+		return err
+	}
+
+	if len(rest) != 0 {
+		return ErrCustomIDMalformed
+	}
+	return nil
+}
+
+func (codec *CustomIDCodec) sign(prefix string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, codec.secret)
+	mac.Write([]byte(prefix))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)[:customIDSignatureBytes]
+}
+
+func appendCustomIDValue(dst []byte, value reflect.Value) ([]byte, error) {
+	if !value.IsValid() {
+		return nil, errors.New("custom ID state cannot be nil")
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			return append(dst, 1), nil
+		}
+		return append(dst, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(dst, value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(dst, value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.LittleEndian.AppendUint64(dst, math.Float64bits(value.Float())), nil
+	case reflect.String:
+		dst = binary.AppendUvarint(dst, uint64(value.Len()))
+		return append(dst, value.String()...), nil
+	case reflect.Struct:
+		var err error
+		for i := range value.NumField() {
+			if !value.Type().Field(i).IsExported() {
+				continue
+			}
+
+			dst, err = appendCustomIDValue(dst, value.Field(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil, errors.New("custom ID state cannot be nil pointer")
+		}
+		return appendCustomIDValue(dst, value.Elem())
+	}
+
+	return nil, fmt.Errorf("unsupported custom ID state type: %s", value.Type())
+}
+
+func readCustomIDValue(src []byte, value reflect.Value) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.Bool:
+		if len(src) < 1 {
+			return nil, ErrCustomIDMalformed
+		}
+		value.SetBool(src[0] == 1)
+		return src[1:], nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, n := binary.Varint(src)
+		if n <= 0 || value.OverflowInt(v) {
+			return nil, ErrCustomIDMalformed
+		}
+		value.SetInt(v)
+		return src[n:], nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, n := binary.Uvarint(src)
+		if n <= 0 || value.OverflowUint(v) {
+			return nil, ErrCustomIDMalformed
+		}
+		value.SetUint(v)
+		return src[n:], nil
+	case reflect.Float32, reflect.Float64:
+		if len(src) < 8 {
+			return nil, ErrCustomIDMalformed
+		}
+		value.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(src)))
+		return src[8:], nil
+	case reflect.String:
+		length, n := binary.Uvarint(src)
+		if n <= 0 || uint64(len(src)-n) < length {
+			return nil, ErrCustomIDMalformed
+		}
+		value.SetString(string(src[n : n+int(length)]))
+		return src[n+int(length):], nil
+	case reflect.Struct:
+		var err error
+		for i := range value.NumField() {
+			if !value.Type().Field(i).IsExported() {
+				continue
+			}
+
+			src, err = readCustomIDValue(src, value.Field(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return src, nil
+	case reflect.Pointer:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return readCustomIDValue(src, value.Elem())
+	}
+
+	return nil, fmt.Errorf("unsupported custom ID state type: %s", value.Type())
+}
+
+// Registers component route for custom IDs produced by codec.Encode(prefix, ...).
+// Handler receives decoded state or error (check it with errors.Is against ErrCustomIDTampered, ErrCustomIDExpired, etc.).
+func RegisterStatefulComponent[T any](client *BaseClient, codec *CustomIDCodec, prefix string, handler func(itx *ComponentInteraction, state T, err error)) error {
+	return client.RegisterComponentRoute(prefix+ROUTE_SEGMENT_SEPARATOR+"{state}", func(itx *ComponentInteraction) {
+		var state T
+		err := codec.Decode(itx.Data.CustomID, &state)
+		handler(itx, state, err)
+	})
+}
+
+// Registers modal route for custom IDs produced by codec.Encode(prefix, ...). See [RegisterStatefulComponent].
+func RegisterStatefulModal[T any](client *BaseClient, codec *CustomIDCodec, prefix string, handler func(itx *ModalInteraction, state T, err error)) error {
+	return client.RegisterModalRoute(prefix+ROUTE_SEGMENT_SEPARATOR+"{state}", func(itx *ModalInteraction) {
+		var state T
+		err := codec.Decode(itx.Data.CustomID, &state)
+		handler(itx, state, err)
+	})
+}
@@ -0,0 +1,60 @@
+package tempest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPageState struct {
+	Offset int
+	Target Snowflake
+	Action uint8
+	Query  string
+	hidden bool
+}
+
+func TestCustomIDCodec(t *testing.T) {
+	codec := NewCustomIDCodec([]byte("0123456789abcdef"))
+	state := testPageState{Offset: -25, Target: 1234567890123456789, Action: 3, Query: "cats"}
+
+	customID, err := codec.Encode("page:next", state, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(customID, "page:next:") || len(customID) > 100 {
+		t.Fatalf("unexpected custom ID: %q", customID)
+	}
+
+	var decoded testPageState
+	if err := codec.Decode(customID, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded != state {
+		t.Errorf("expected %+v, got %+v", state, decoded)
+	}
+
+	if err := codec.Decode("page:prev"+customID[len("page:next"):], &decoded); !errors.Is(err, ErrCustomIDTampered) {
+		t.Errorf("expected tampered error for moved prefix, got %v", err)
+	}
+
+	if err := NewCustomIDCodec([]byte("fedcba9876543210")).Decode(customID, &decoded); !errors.Is(err, ErrCustomIDTampered) {
+		t.Errorf("expected tampered error for different secret, got %v", err)
+	}
+
+	codec.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if err := codec.Decode(customID, &decoded); !errors.Is(err, ErrCustomIDExpired) {
+		t.Errorf("expected expired error, got %v", err)
+	}
+
+	if _, err := codec.Encode("page", strings.Repeat("x", 100), 0); !errors.Is(err, ErrCustomIDTooLong) {
+		t.Errorf("expected too long error, got %v", err)
+	}
+
+	if _, err := codec.Encode("page", nil, 0); err == nil {
+		t.Error("expected error for nil state")
+	}
+}
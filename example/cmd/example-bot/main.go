@@ -21,7 +21,7 @@ func loadCommands(client *tempest.BaseClient) {
 
 	client.RegisterCommand(command.Add)
 	client.RegisterCommand(command.AutoComplete)
-	client.RegisterCommand(command.Avatar)
+	client.RegisterUserCommand(command.Avatar)
 	client.RegisterCommand(command.Defer)
 	client.RegisterCommand(command.Dynamic)
 	client.RegisterCommand(command.Fetch)
@@ -5,13 +5,10 @@ import (
 )
 
 var Avatar tempest.Command = tempest.Command{
-	Type: tempest.USER_COMMAND_TYPE,
 	Name: "avatar",
-	SlashCommandHandler: func(itx *tempest.CommandInteraction) {
-		user := itx.ResolveUser(itx.Data.TargetID)
-
+	UserCommandHandler: func(itx *tempest.CommandInteraction, user tempest.User, _ *tempest.Member) error {
 		avatar := user.AvatarURL()
-		itx.SendReply(tempest.ResponseMessageData{
+		return itx.SendReply(tempest.ResponseMessageData{
 			Embeds: []tempest.Embed{
 				{
 					Title: user.Username + "'s avatar",
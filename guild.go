@@ -8,6 +8,12 @@ type UnavailableGuild struct {
 	Unavailable bool      `json:"unavailable"`
 }
 
+// https://docs.discord.com/developers/resources/guild#ban-object
+type Ban struct {
+	Reason string `json:"reason,omitempty"`
+	User   User   `json:"user"`
+}
+
 // https://docs.discord.com/developers/resources/guild#guild-object-default-message-notification-level
 type MessageNotificationLevel uint8
 
@@ -40,6 +40,15 @@ func NewHTTPClient(opt HTTPClientOptions) *HTTPClient {
 			ComponentHandler:           opt.ComponentHandler,
 			ModalHandler:               opt.ModalHandler,
 			Logger:                     opt.Logger,
+			RestOptions:                opt.RestOptions,
+			AutoDefer:                  opt.AutoDefer,
+			Middlewares:                opt.Middlewares,
+			ErrorHandler:               opt.ErrorHandler,
+			CooldownStore:              opt.CooldownStore,
+			OnCooldown:                 opt.OnCooldown,
+			OnMaxConcurrency:           opt.OnMaxConcurrency,
+			ListenerStore:              opt.ListenerStore,
+			Catalog:                    opt.Catalog,
 		}),
 		PublicKey: discordPublicKey,
 		bufferPool: &sync.Pool{
@@ -268,48 +277,32 @@ func (client *HTTPClient) commandInteractionHandler(interaction CommandInteracti
 
 	client.tracef("Received command interaction (ID = %s, Command = \"%s\") - moved to target command's handler.", itx.ID.String(), itx.Data.Name)
 
-	if client.preCommandHandler != nil && !client.preCommandHandler(command, &itx) {
-		return
-	}
-
-	if client.trace {
-		start := time.Now()
-		command.SlashCommandHandler(&itx)
-		client.tracef("Command %s execution took %v", command.Name, time.Since(start))
-	} else {
-		command.SlashCommandHandler(&itx)
-	}
-
-	if client.postCommandHandler != nil {
-		client.postCommandHandler(command, &itx)
-	}
+	client.runCommand(&itx, command)
 }
 
 func (client *HTTPClient) autoCompleteInteractionHandler(interaction CommandInteraction) []CommandOptionChoice {
 	itx, command, available := client.handleInteraction(interaction)
-	if !available || command.AutoCompleteHandler == nil {
+	if !available || command.AutoCompleteHandler == nil && command.AutoCompleteHandlerE == nil {
 		client.tracef("Dropped auto complete interaction (ID = %s). You see this trace message because client received slash command's auto complete interaction but there's no defined handler for it.", itx.ID.String())
 		return nil
 	}
 
 	client.tracef("Received slash command's auto complete interaction (ID = %s, Command = \"%s\") - moved to target (sub) command auto complete handler.", itx.ID.String(), itx.Data.Name)
-	return command.AutoCompleteHandler(itx)
+	return client.runAutoComplete(itx, command)
 }
 
 func (client *HTTPClient) componentInteractionHandler(interaction ComponentInteraction, responseCh chan []byte) {
 	if fn, ok := client.staticComponents.Get(interaction.Data.CustomID); ok {
 		client.tracef("Received component interaction (ID = %s, CustomID = \"%s\") with matching custom ID for static handler - moved to registered handler.", interaction.ID.String(), interaction.Data.CustomID)
-		fn(interaction)
+		client.runComponent(&interaction, func(itx *ComponentInteraction) { fn(*itx) })
 		return
 	}
 
 	handler, isQueued := client.queuedComponents.Get(interaction.Data.CustomID)
-	if isQueued && time.Now().After(handler.Expire) {
+	if isQueued && client.sweeper.expired(handler.timer, time.Now()) {
+		// Sweeper may not have caught up yet - expire listener right away, so late clicks fall through to other handlers.
 		isQueued = false
-		client.queuedComponents.Delete(interaction.Data.CustomID)
-		if handler.OnTimeout != nil {
-			go handler.OnTimeout()
-		}
+		go handler.OnTimeout()
 	}
 
 	hasGlobal := client.componentHandler != nil
@@ -317,19 +310,26 @@ func (client *HTTPClient) componentInteractionHandler(interaction ComponentInter
 	if isQueued {
 		client.tracef("Received component interaction (ID = %s, CustomID = \"%s\") with matching custom ID for dynamic handler - moved to listener.", interaction.ID.String(), interaction.Data.CustomID)
 
-		select {
-		case responseCh <- bodyAcknowledgeResponse:
-		default:
+		if !handler.ManualAck {
+			if err := interaction.DeferUpdate(); err != nil {
+				client.tracef("failed to send deferred update message response: %v", err)
+			}
 		}
 
-		interaction.deferred = true
-		handler.Handler(&interaction)
+		client.runComponent(&interaction, handler.Handler)
+		return
+	}
+
+	if route, ok := client.componentRoutes.match(interaction.Data.CustomID); ok {
+		client.tracef("Received component interaction (ID = %s, CustomID = \"%s\") matching %q route - moved to route handler.", interaction.ID.String(), interaction.Data.CustomID, route.pattern)
+		interaction.params = route.params
+		client.runComponent(&interaction, route.handler)
 		return
 	}
 
 	if hasGlobal {
 		client.tracef("Received component interaction (ID = %s, CustomID = \"%s\") - moved to defined component handler.", interaction.ID.String(), interaction.Data.CustomID)
-		client.componentHandler(&interaction)
+		client.runComponent(&interaction, client.componentHandler)
 		return
 	}
 
@@ -339,17 +339,15 @@ func (client *HTTPClient) componentInteractionHandler(interaction ComponentInter
 func (client *HTTPClient) modalInteractionHandler(interaction ModalInteraction, responseCh chan []byte) {
 	if fn, ok := client.staticModals.Get(interaction.Data.CustomID); ok {
 		client.tracef("Received modal interaction (ID = %s, CustomID = \"%s\") with matching custom ID for static handler - moved to registered handler.", interaction.ID.String(), interaction.Data.CustomID)
-		fn(interaction)
+		client.runModal(&interaction, func(itx *ModalInteraction) { fn(*itx) })
 		return
 	}
 
 	handler, isQueued := client.queuedModals.Get(interaction.Data.CustomID)
-	if isQueued && time.Now().After(handler.Expire) {
+	if isQueued && client.sweeper.expired(handler.timer, time.Now()) {
+		// Sweeper may not have caught up yet - expire listener right away, so late clicks fall through to other handlers.
 		isQueued = false
-		client.queuedModals.Delete(interaction.Data.CustomID)
-		if handler.OnTimeout != nil {
-			go handler.OnTimeout()
-		}
+		go handler.OnTimeout()
 	}
 
 	hasGlobal := client.modalHandler != nil
@@ -357,19 +355,26 @@ func (client *HTTPClient) modalInteractionHandler(interaction ModalInteraction,
 	if isQueued {
 		client.tracef("Received modal interaction (ID = %s, CustomID = \"%s\") with matching custom ID for dynamic handler - moved to listener.", interaction.ID.String(), interaction.Data.CustomID)
 
-		select {
-		case responseCh <- bodyAcknowledgeResponse:
-		default:
+		if !handler.ManualAck {
+			if err := interaction.DeferUpdate(); err != nil {
+				client.tracef("failed to send deferred update message response: %v", err)
+			}
 		}
 
-		interaction.deferred = true
-		handler.Handler(&interaction)
+		client.runModal(&interaction, handler.Handler)
+		return
+	}
+
+	if route, ok := client.modalRoutes.match(interaction.Data.CustomID); ok {
+		client.tracef("Received modal interaction (ID = %s, CustomID = \"%s\") matching %q route - moved to route handler.", interaction.ID.String(), interaction.Data.CustomID, route.pattern)
+		interaction.params = route.params
+		client.runModal(&interaction, route.handler)
 		return
 	}
 
 	if hasGlobal {
 		client.tracef("Received modal interaction (ID = %s, CustomID = \"%s\") - moved to defined modal handler.", interaction.ID.String(), interaction.Data.CustomID)
-		client.modalHandler(&interaction)
+		client.runModal(&interaction, client.modalHandler)
 		return
 	}
 
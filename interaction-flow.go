@@ -0,0 +1,499 @@
+package tempest
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Special step names that can be returned from flow step handlers.
+const (
+	FLOW_STAY   = ""        // Stays at current step (and renders it again).
+	FLOW_BACK   = "@back"   // Returns to previous message step.
+	FLOW_CANCEL = "@cancel" // Cancels flow (runs Flow.OnCancel).
+	FLOW_FINISH = "@finish" // Finishes flow (runs Flow.OnFinish).
+)
+
+const (
+	flowCustomIDPrefix       = "tempest-flow"
+	defaultFlowTimeout       = time.Minute * 14 // Just below 15 minutes limit of interaction tokens, so flow message can still be edited after timeout.
+	defaultFlowStepTimeout   = time.Minute * 3
+	defaultFlowNotOwnerReply = "Only the person who started this can use it."
+)
+
+// Single state of flow. Each step renders either message (with Message) or modal (with Modal) and decides
+// where to go next in OnComponent/OnModal, by returning name of next step or one of FLOW_* constants.
+//
+// Components of message step need custom IDs created with ctx.CustomID - only those are listened to.
+// Modal steps can only be entered from component click (or as the very first step of flow started from command),
+// as Discord doesn't allow responding with modal to modal submit.
+type FlowStep[S any] struct {
+	Message     func(ctx *FlowContext[S]) (ResponseMessageData, error)
+	Modal       func(ctx *FlowContext[S]) (ResponseModalData, error) // Custom ID of returned modal is set by flow.
+	OnComponent func(ctx *FlowContext[S], itx *ComponentInteraction) (next string, err error)
+	OnModal     func(ctx *FlowContext[S], itx *ModalInteraction) (next string, err error)
+	Timeout     time.Duration // How long to wait for user at this step. By default: Flow.StepTimeout.
+}
+
+// Declarative multi-step conversation (wizard) built on top of dynamic listeners.
+// Flow can be reused - each RunFlow call creates separate conversation with its own state.
+type Flow[S any] struct {
+	Steps       map[string]FlowStep[S]
+	Start       string        // Name of the first step.
+	Timeout     time.Duration // Max duration of whole flow. By default: 14 minutes.
+	StepTimeout time.Duration // Default timeout of each step. By default: 3 minutes.
+	Ephemeral   bool          // Whether flow message should be ephemeral.
+
+	// Functions that run once flow ends. Interaction that ended flow isn't acknowledged yet (unless it was modal submit
+	// used to start flow), so you can respond to it. If you don't - flow disables all components of its message.
+	OnFinish func(ctx *FlowContext[S], itx *Interaction) error
+	OnCancel func(ctx *FlowContext[S], itx *Interaction) error
+
+	OnTimeout func(ctx *FlowContext[S]) // Function that runs when user stopped responding. Components of flow message get disabled afterwards.
+}
+
+// Per conversation data, passed to all step functions.
+type FlowContext[S any] struct {
+	State   S         // Custom state of conversation, you can freely modify it inside step handlers.
+	Step    string    // Name of current step.
+	UserID  Snowflake // ID of user who started flow.
+	prefix  string
+	history []string // Names of previously visited message steps, used by FLOW_BACK.
+}
+
+// Returns custom ID that flow listens to. Use it for all components in flow messages.
+func (ctx *FlowContext[S]) CustomID(name string) string {
+	return ctx.prefix + name
+}
+
+// Returns button that moves flow back to previous message step.
+func (ctx *FlowContext[S]) BackButton(label string) ButtonComponent {
+	return ButtonComponent{
+		Type:     BUTTON_COMPONENT_TYPE,
+		Style:    SECONDARY_BUTTON_STYLE,
+		CustomID: ctx.prefix + FLOW_BACK,
+		Label:    label,
+		Disabled: len(ctx.history) == 0,
+	}
+}
+
+// Returns button that cancels flow.
+func (ctx *FlowContext[S]) CancelButton(label string) ButtonComponent {
+	return ButtonComponent{
+		Type:     BUTTON_COMPONENT_TYPE,
+		Style:    DANGER_BUTTON_STYLE,
+		CustomID: ctx.prefix + FLOW_CANCEL,
+		Label:    label,
+	}
+}
+
+// Returns whether FLOW_BACK would move to another step.
+func (ctx *FlowContext[S]) CanGoBack() bool {
+	return len(ctx.history) != 0
+}
+
+type flowRun[S any] struct {
+	flow        *Flow[S]
+	ctx         *FlowContext[S]
+	client      *BaseClient
+	last        *Interaction // Interaction whose original response is flow message.
+	content     ResponseMessageData
+	deadline    time.Time
+	messageStep string // Last rendered message step (its components stay active during modal steps).
+	componentID []string
+	modalID     string
+	generation  uint64
+	mu          sync.Mutex
+	done        bool
+}
+
+// Starts new conversation for flow, responding to itx with its first step.
+func RunFlow[S any](itx *Interaction, flow *Flow[S], state S) error {
+	if _, ok := flow.Steps[flow.Start]; !ok {
+		return fmt.Errorf("flow start step %q doesn't exist", flow.Start)
+	}
+
+	timeout := flow.Timeout
+	if timeout <= 0 {
+		timeout = defaultFlowTimeout
+	}
+
+	run := &flowRun[S]{
+		flow:     flow,
+		client:   itx.BaseClient,
+		deadline: time.Now().Add(timeout),
+		ctx: &FlowContext[S]{
+			State:  state,
+			UserID: itx.BaseUser().ID,
+			prefix: flowCustomIDPrefix + ROUTE_SEGMENT_SEPARATOR + itx.ID.String() + ROUTE_SEGMENT_SEPARATOR,
+		},
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return run.enter(itx, flow.Start, true)
+}
+
+// Moves conversation to given step and responds to itx with it.
+func (run *flowRun[S]) enter(itx *Interaction, name string, canOpenModal bool) error {
+	step, ok := run.flow.Steps[name]
+	if !ok {
+		return fmt.Errorf("flow step %q doesn't exist", name)
+	}
+
+	previous := run.ctx.Step
+	run.ctx.Step = name
+
+	if step.Modal != nil {
+		if !canOpenModal {
+			run.ctx.Step = previous
+			return fmt.Errorf("flow step %q is modal, but it can't be opened in response to modal submit", name)
+		}
+
+		modal, err := step.Modal(run.ctx)
+		if err != nil {
+			run.ctx.Step = previous
+			return err
+		}
+
+		modal.CustomID = run.ctx.prefix + "@modal" + ROUTE_SEGMENT_SEPARATOR + name
+		if err := itx.SendModal(modal); err != nil {
+			run.ctx.Step = previous
+			return err
+		}
+
+		return run.arm(modal.CustomID)
+	}
+
+	content, err := step.Message(run.ctx)
+	if err != nil {
+		run.ctx.Step = previous
+		return err
+	}
+
+	if err := run.show(itx, content); err != nil {
+		run.ctx.Step = previous
+		return err
+	}
+
+	run.messageStep = name
+	return run.arm("")
+}
+
+// Displays content as flow message - sends it as reply when there's no flow message yet, updates it otherwise.
+func (run *flowRun[S]) show(itx *Interaction, content ResponseMessageData) error {
+	var err error
+	switch {
+	case run.last == nil:
+		err = itx.SendReply(content, run.flow.Ephemeral, nil)
+		run.last = itx
+	case itx.Responded():
+		err = run.last.EditReply(content, false)
+	default:
+		err = itx.UpdateMessage(content, nil)
+		run.last = itx
+	}
+
+	if err == nil {
+		run.content = content
+	}
+	return err
+}
+
+// Replaces listeners of previous step with listeners of current one.
+func (run *flowRun[S]) arm(modalID string) error {
+	run.disarm()
+	run.generation++
+	generation := run.generation
+
+	timeout := run.flow.Steps[run.ctx.Step].Timeout
+	if timeout <= 0 {
+		timeout = cmp.Or(run.flow.StepTimeout, defaultFlowStepTimeout)
+	}
+	timeout = min(timeout, time.Until(run.deadline))
+
+	onTimeout := func() { run.onTimeout(generation) }
+	run.componentID = collectCustomIDs(run.content.Components, run.ctx.prefix)
+	if len(run.componentID) != 0 {
+		err := run.client.awaitComponent(run.componentID, timeout, func(itx *ComponentInteraction) bool {
+			run.onComponent(generation, itx)
+			return false
+		}, onTimeout, true)
+		if err != nil {
+			run.componentID = nil
+			return err
+		}
+	}
+
+	if modalID != "" {
+		err := run.client.awaitModal([]string{modalID}, timeout, func(itx *ModalInteraction) bool {
+			run.onModal(generation, itx)
+			return false
+		}, onTimeout, true)
+		if err != nil {
+			return err
+		}
+		run.modalID = modalID
+	}
+
+	return nil
+}
+
+// Removes active listeners without running their timeout callbacks.
+func (run *flowRun[S]) disarm() {
+	run.client.cancelComponentListeners(run.componentID...)
+	run.componentID = nil
+
+	if run.modalID != "" {
+		run.client.cancelModalListeners(run.modalID)
+		run.modalID = ""
+	}
+}
+
+func (run *flowRun[S]) onComponent(generation uint64, itx *ComponentInteraction) {
+	if itx.BaseUser().ID != run.ctx.UserID {
+		itx.SendLinearReply(defaultFlowNotOwnerReply, true)
+		return
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	if run.done || generation != run.generation {
+		itx.DeferUpdate()
+		return
+	}
+
+	// Components belong to last message step, even if user dismissed modal opened from there.
+	run.ctx.Step = run.messageStep
+
+	var next string
+	switch id := strings.TrimPrefix(itx.Data.CustomID, run.ctx.prefix); id {
+	case FLOW_BACK, FLOW_CANCEL:
+		next = id
+	default:
+		handler := run.flow.Steps[run.messageStep].OnComponent
+		if handler == nil {
+			itx.DeferUpdate()
+			return
+		}
+
+		var err error
+		if next, err = handler(run.ctx, itx); err != nil {
+			run.client.handleError(itx.Interaction, err)
+			return
+		}
+	}
+
+	run.transition(itx.Interaction, next, true)
+}
+
+func (run *flowRun[S]) onModal(generation uint64, itx *ModalInteraction) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	if run.done || generation != run.generation {
+		itx.DeferUpdate()
+		return
+	}
+
+	handler := run.flow.Steps[run.ctx.Step].OnModal
+	if handler == nil {
+		run.client.handleError(itx.Interaction, fmt.Errorf("flow step %q has no modal handler", run.ctx.Step))
+		return
+	}
+
+	next, err := handler(run.ctx, itx)
+	if err != nil {
+		run.client.handleError(itx.Interaction, err)
+		return
+	}
+
+	run.transition(itx.Interaction, next, false)
+}
+
+func (run *flowRun[S]) transition(itx *Interaction, next string, canOpenModal bool) {
+	var err error
+	switch next {
+	case FLOW_FINISH:
+		err = run.end(itx, run.flow.OnFinish)
+	case FLOW_CANCEL:
+		err = run.end(itx, run.flow.OnCancel)
+	case FLOW_BACK:
+		if run.ctx.Step != run.messageStep && run.messageStep != "" {
+			// Going back from modal step shows message it was opened from.
+			err = run.enter(itx, run.messageStep, canOpenModal)
+			break
+		}
+
+		if len(run.ctx.history) == 0 {
+			err = run.enter(itx, run.ctx.Step, canOpenModal)
+			break
+		}
+
+		target := run.ctx.history[len(run.ctx.history)-1]
+		run.ctx.history = run.ctx.history[:len(run.ctx.history)-1]
+		err = run.enter(itx, target, canOpenModal)
+	case FLOW_STAY:
+		err = run.enter(itx, run.ctx.Step, canOpenModal)
+	default:
+		from := run.messageStep
+		if err = run.enter(itx, next, canOpenModal); err == nil && from != "" && run.messageStep != from {
+			run.ctx.history = append(run.ctx.history, from)
+		}
+	}
+
+	if err != nil {
+		run.client.handleError(itx, err)
+	}
+}
+
+func (run *flowRun[S]) end(itx *Interaction, handler func(ctx *FlowContext[S], itx *Interaction) error) error {
+	run.done = true
+	run.disarm()
+
+	if handler != nil {
+		if err := handler(run.ctx, itx); err != nil {
+			return err
+		}
+	}
+
+	if itx.Responded() || itx.Deferred() {
+		return nil
+	}
+
+	if run.last == nil {
+		return errors.New("flow ended without message to update - respond to interaction in OnFinish/OnCancel")
+	}
+	return run.show(itx, disableMessageComponents(run.content))
+}
+
+func (run *flowRun[S]) onTimeout(generation uint64) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	if run.done || generation != run.generation {
+		return
+	}
+
+	run.done = true
+	run.disarm()
+
+	if run.flow.OnTimeout != nil {
+		run.flow.OnTimeout(run.ctx)
+	}
+
+	if run.last != nil {
+		if err := run.last.EditReply(disableMessageComponents(run.content), false); err != nil {
+			run.client.tracef("Flow failed to disable components after timeout: %v", err)
+		}
+	}
+}
+
+// Finds custom IDs (with given prefix) of all interactive components inside message components.
+func collectCustomIDs(components []MessageComponent, prefix string) []string {
+	var ids []string
+	var walkRow func(row ActionRowComponent)
+	walkRow = func(row ActionRowComponent) {
+		for _, child := range row.Components {
+			var id string
+			switch c := child.(type) {
+			case ButtonComponent:
+				id = c.CustomID
+			case StringSelectComponent:
+				id = c.CustomID
+			case SelectComponent:
+				id = c.CustomID
+			}
+
+			if strings.HasPrefix(id, prefix) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	var walk func(component AnyComponent)
+	walk = func(component AnyComponent) {
+		switch c := component.(type) {
+		case ActionRowComponent:
+			walkRow(c)
+		case SectionComponent:
+			if button, ok := c.Accessory.(ButtonComponent); ok && strings.HasPrefix(button.CustomID, prefix) {
+				ids = append(ids, button.CustomID)
+			}
+		case ContainerComponent:
+			for _, child := range c.Components {
+				walk(child)
+			}
+		}
+	}
+
+	for _, component := range components {
+		walk(component)
+	}
+	return ids
+}
+
+// Returns copy of message with all buttons & select menus disabled.
+func disableMessageComponents(content ResponseMessageData) ResponseMessageData {
+	disableRow := func(row ActionRowComponent) ActionRowComponent {
+		children := make([]ActionRowChildComponent, len(row.Components))
+		for i, child := range row.Components {
+			switch c := child.(type) {
+			case ButtonComponent:
+				c.Disabled = c.Style != LINK_BUTTON_STYLE
+				children[i] = c
+			case StringSelectComponent:
+				c.Disabled = true
+				children[i] = c
+			case SelectComponent:
+				c.Disabled = true
+				children[i] = c
+			default:
+				children[i] = child
+			}
+		}
+		row.Components = children
+		return row
+	}
+
+	disableSection := func(section SectionComponent) SectionComponent {
+		if button, ok := section.Accessory.(ButtonComponent); ok && button.Style != LINK_BUTTON_STYLE {
+			button.Disabled = true
+			section.Accessory = button
+		}
+		return section
+	}
+
+	components := make([]MessageComponent, len(content.Components))
+	for i, component := range content.Components {
+		switch c := component.(type) {
+		case ActionRowComponent:
+			components[i] = disableRow(c)
+		case SectionComponent:
+			components[i] = disableSection(c)
+		case ContainerComponent:
+			children := make([]ContainerChildComponent, len(c.Components))
+			for j, child := range c.Components {
+				switch cc := child.(type) {
+				case ActionRowComponent:
+					children[j] = disableRow(cc)
+				case SectionComponent:
+					children[j] = disableSection(cc)
+				default:
+					children[j] = child
+				}
+			}
+			c.Components = children
+			components[i] = c
+		default:
+			components[i] = component
+		}
+	}
+
+	content.Components = components
+	return content
+}
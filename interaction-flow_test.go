@@ -0,0 +1,145 @@
+package tempest
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCollectCustomIDs(t *testing.T) {
+	components := []MessageComponent{
+		ActionRowComponent{Type: ACTION_ROW_COMPONENT_TYPE, Components: []ActionRowChildComponent{
+			ButtonComponent{Type: BUTTON_COMPONENT_TYPE, CustomID: "flow:next"},
+			ButtonComponent{Type: BUTTON_COMPONENT_TYPE, Style: LINK_BUTTON_STYLE, URL: "https://discord.com"},
+			ButtonComponent{Type: BUTTON_COMPONENT_TYPE, CustomID: "other:next"},
+		}},
+		ContainerComponent{Type: CONTAINER_COMPONENT_TYPE, Components: []ContainerChildComponent{
+			SectionComponent{Type: SECTION_COMPONENT_TYPE, Accessory: ButtonComponent{Type: BUTTON_COMPONENT_TYPE, CustomID: "flow:edit"}},
+			ActionRowComponent{Type: ACTION_ROW_COMPONENT_TYPE, Components: []ActionRowChildComponent{
+				StringSelectComponent{Type: STRING_SELECT_COMPONENT_TYPE, CustomID: "flow:pick"},
+			}},
+		}},
+	}
+
+	expected := []string{"flow:next", "flow:edit", "flow:pick"}
+	if ids := collectCustomIDs(components, "flow:"); !slices.Equal(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+}
+
+func TestDisableMessageComponents(t *testing.T) {
+	content := ResponseMessageData{Components: []MessageComponent{
+		ActionRowComponent{Type: ACTION_ROW_COMPONENT_TYPE, Components: []ActionRowChildComponent{
+			ButtonComponent{Type: BUTTON_COMPONENT_TYPE, CustomID: "flow:next"},
+			ButtonComponent{Type: BUTTON_COMPONENT_TYPE, Style: LINK_BUTTON_STYLE, URL: "https://discord.com"},
+		}},
+		ContainerComponent{Type: CONTAINER_COMPONENT_TYPE, Components: []ContainerChildComponent{
+			SectionComponent{Type: SECTION_COMPONENT_TYPE, Accessory: ButtonComponent{Type: BUTTON_COMPONENT_TYPE, CustomID: "flow:edit"}},
+			ActionRowComponent{Type: ACTION_ROW_COMPONENT_TYPE, Components: []ActionRowChildComponent{
+				StringSelectComponent{Type: STRING_SELECT_COMPONENT_TYPE, CustomID: "flow:pick"},
+			}},
+		}},
+	}}
+
+	disabled := disableMessageComponents(content)
+
+	row := disabled.Components[0].(ActionRowComponent)
+	if !row.Components[0].(ButtonComponent).Disabled || row.Components[1].(ButtonComponent).Disabled {
+		t.Errorf("expected only non-link button to be disabled, got %+v", row.Components)
+	}
+
+	container := disabled.Components[1].(ContainerComponent)
+	if button := container.Components[0].(SectionComponent).Accessory.(ButtonComponent); !button.Disabled {
+		t.Error("expected section accessory button to be disabled")
+	}
+
+	if menu := container.Components[1].(ActionRowComponent).Components[0].(StringSelectComponent); !menu.Disabled {
+		t.Error("expected select menu inside container to be disabled")
+	}
+
+	if content.Components[0].(ActionRowComponent).Components[0].(ButtonComponent).Disabled {
+		t.Error("input message got mutated")
+	}
+}
+
+func TestFlowTransitions(t *testing.T) {
+	client := &BaseClient{
+		staticComponents: NewSharedMap[string, func(ComponentInteraction)](),
+		queuedComponents: NewSharedMap[string, *queuedComponent](),
+		staticModals:     NewSharedMap[string, func(ModalInteraction)](),
+		queuedModals:     NewSharedMap[string, *queuedModal](),
+		sweeper:          interactionSweeper{signal: make(chan struct{}, 1)},
+	}
+
+	var lastErr error
+	client.errorHandler = func(_ *Interaction, err error) { lastErr = err }
+
+	var responses []ResponseType
+	newInteraction := func(kind InteractionType) *Interaction {
+		return &Interaction{BaseClient: client, Type: kind, responder: func(res Response) error {
+			responses = append(responses, res.Type)
+			return nil
+		}}
+	}
+
+	message := func(ctx *FlowContext[int]) (ResponseMessageData, error) {
+		return ResponseMessageData{Components: []MessageComponent{ActionRowComponent{
+			Type:       ACTION_ROW_COMPONENT_TYPE,
+			Components: []ActionRowChildComponent{ctx.BackButton("Back"), ButtonComponent{Type: BUTTON_COMPONENT_TYPE, CustomID: ctx.CustomID("go")}},
+		}}}, nil
+	}
+
+	flow := &Flow[int]{
+		Start: "menu",
+		Steps: map[string]FlowStep[int]{
+			"menu":    {Message: message},
+			"details": {Message: message},
+			"form":    {Modal: func(*FlowContext[int]) (ResponseModalData, error) { return ResponseModalData{Title: "Form"}, nil }},
+		},
+	}
+
+	run := &flowRun[int]{flow: flow, client: client, ctx: &FlowContext[int]{prefix: "flow:"}}
+	if err := run.enter(newInteraction(APPLICATION_COMMAND_INTERACTION_TYPE), flow.Start, true); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		next      string
+		itxType   InteractionType
+		canModal  bool
+		step      string
+		history   []string
+		response  ResponseType
+		expectErr bool
+	}{
+		{"details", MESSAGE_COMPONENT_INTERACTION_TYPE, true, "details", []string{"menu"}, UPDATE_MESSAGE_RESPONSE_TYPE, false},
+		{FLOW_STAY, MESSAGE_COMPONENT_INTERACTION_TYPE, true, "details", []string{"menu"}, UPDATE_MESSAGE_RESPONSE_TYPE, false},
+		{FLOW_BACK, MESSAGE_COMPONENT_INTERACTION_TYPE, true, "menu", nil, UPDATE_MESSAGE_RESPONSE_TYPE, false},
+		{FLOW_BACK, MESSAGE_COMPONENT_INTERACTION_TYPE, true, "menu", nil, UPDATE_MESSAGE_RESPONSE_TYPE, false}, // Nothing to go back to.
+		{"form", MESSAGE_COMPONENT_INTERACTION_TYPE, true, "form", nil, MODAL_RESPONSE_TYPE, false},
+		{FLOW_BACK, MODAL_SUBMIT_INTERACTION_TYPE, false, "menu", nil, UPDATE_MESSAGE_RESPONSE_TYPE, false}, // Modal goes back to message it was opened from.
+		{"form", MODAL_SUBMIT_INTERACTION_TYPE, false, "menu", nil, 0, true},                                // Modal can't answer modal submit.
+	}
+
+	for i, step := range steps {
+		responses, lastErr = nil, nil
+		run.transition(newInteraction(step.itxType), step.next, step.canModal)
+
+		if (lastErr != nil) != step.expectErr {
+			t.Fatalf("#%d (%s): unexpected error state: %v", i, step.next, lastErr)
+		}
+
+		if run.ctx.Step != step.step || !slices.Equal(run.ctx.history, step.history) {
+			t.Fatalf("#%d (%s): expected step %q with history %v, got %q with %v", i, step.next, step.step, step.history, run.ctx.Step, run.ctx.history)
+		}
+
+		if !step.expectErr && (len(responses) != 1 || responses[0] != step.response) {
+			t.Fatalf("#%d (%s): expected single response of type %d, got %v", i, step.next, step.response, responses)
+		}
+	}
+
+	if !client.queuedComponents.Has("flow:go") || !client.queuedComponents.Has("flow:"+FLOW_BACK) {
+		t.Error("expected listeners for components of current message step")
+	}
+
+	run.disarm()
+}
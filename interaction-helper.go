@@ -1,14 +1,15 @@
 package tempest
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
+	"math"
+	"strconv"
+	"strings"
 )
 
 // Returns whether this interaction already was responded to.
 func (itx *Interaction) Responded() bool {
-	return itx.responded
+	responded, _, _ := itx.state()
+	return responded
 }
 
 // Returns user data either from member or from user (depending if interaction was used in a server).
@@ -90,227 +91,102 @@ func (itx *CommandInteraction) ResolveAttachment(id Snowflake) Attachment {
 	return itx.Data.Resolved.Attachments[id]
 }
 
-// Use to let user/member know that bot is processing command.
-// Make ephemeral = true to make notification visible only to target.
-func (itx *CommandInteraction) Defer(ephemeral bool) error {
-	if itx.deferred || itx.responded {
-		return errors.New("interaction has already been responded to or deferred")
+// Returns user targeted by user (context menu) command, with member data when command was used in a server (nil otherwise).
+func (itx *CommandInteraction) TargetUser() (User, *Member, bool) {
+	if itx.Data.Resolved == nil || itx.Data.TargetID == 0 {
+		return User{}, nil, false
 	}
 
-	var flags MessageFlags = 0
-	if ephemeral {
-		flags = EPHEMERAL_MESSAGE_FLAG
+	user, available := itx.Data.Resolved.Users[itx.Data.TargetID]
+	if !available {
+		return User{}, nil, false
 	}
 
-	err := itx.responder(Response{
-		Type: DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
-		Data: &ResponseMessageData{Flags: flags},
-	})
-
-	if err == nil {
-		itx.deferred = true
+	member, available := itx.Data.Resolved.Members[itx.Data.TargetID]
+	if !available {
+		return user, nil, true
 	}
 
-	return err
+	member.User, member.GuildID = &user, itx.GuildID
+	return user, &member, true
 }
 
-// Acknowledges the interaction with a message. Set ephemeral = true to make message visible only to target.
-func (itx *CommandInteraction) SendReply(reply ResponseMessageData, ephemeral bool, files []File) error {
-	if ephemeral {
-		reply.Flags |= EPHEMERAL_MESSAGE_FLAG
-	}
-
-	if itx.responded {
-		return errors.New("interaction has already been responded to")
-	}
-
-	endpoint := "/webhooks/" + itx.ApplicationID.String() + "/" + itx.Token
-
-	if itx.deferred {
-		_, err := itx.BaseClient.Rest.RequestWithFiles(http.MethodPatch, endpoint+"/messages/@original", reply, files)
-		if err == nil {
-			itx.responded = true
-		}
-		return err
+// Returns message targeted by message (context menu) command.
+func (itx *CommandInteraction) TargetMessage() (Message, bool) {
+	if itx.Data.Resolved == nil || itx.Data.TargetID == 0 {
+		return Message{}, false
 	}
 
-	if len(files) > 0 {
-		err := itx.responder(Response{
-			Type: DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
-			Data: &ResponseMessageData{Flags: reply.Flags},
-		})
-		if err != nil {
-			return err
-		}
-
-		itx.deferred = true // Manually set state
-
-		_, err = itx.BaseClient.Rest.RequestWithFiles(http.MethodPatch, endpoint+"/messages/@original", reply, files)
-		if err == nil {
-			itx.responded = true
-		}
-
-		return err
-	}
-
-	err := itx.responder(Response{
-		Type: CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
-		Data: &reply,
-	})
-
-	if err == nil {
-		itx.responded = true
-	}
-
-	return err
+	message, available := itx.Data.Resolved.Messages[itx.Data.TargetID]
+	return message, available
 }
 
-func (itx *CommandInteraction) SendLinearReply(content string, ephemeral bool) error {
-	return itx.SendReply(ResponseMessageData{
-		Content: content,
-	}, ephemeral, nil)
-}
-
-func (itx *CommandInteraction) SendModal(modal ResponseModalData) error {
-	err := itx.responder(Response{
-		Type: MODAL_RESPONSE_TYPE,
-		Data: &modal,
-	})
-
-	if err == nil {
-		itx.responded = true
-	}
-
-	return err
+// Warning! This method is only for handling auto complete interaction which is a part of command logic.
+// Returns option name and its value of triggered option. Option name is always of string type but you'll need to check type of value.
+// It returns empty name and nil value when no option is focused (see FocusedOption).
+func (itx *CommandInteraction) GetFocusedValue() (string, any) {
+	option, _ := itx.FocusedOption()
+	return option.Name, option.Value
 }
 
-func (itx *CommandInteraction) EditReply(content ResponseMessageData, ephemeral bool) error {
-	if ephemeral {
-		content.Flags |= EPHEMERAL_MESSAGE_FLAG
+// Returns option that user is currently typing into (only available in auto complete interactions).
+func (itx *CommandInteraction) FocusedOption() (CommandInteractionOption, bool) {
+	for _, option := range itx.Data.Options {
+		if option.Focused {
+			return option, true
+		}
 	}
-
-	_, err := itx.BaseClient.Rest.Request(http.MethodPatch, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token+"/messages/@original", content)
-	return err
+	return CommandInteractionOption{}, false
 }
 
-func (itx *CommandInteraction) EditLinearReply(content string, ephemeral bool) error {
-	return itx.EditReply(ResponseMessageData{
-		Content: content,
-	}, ephemeral)
-}
-
-func (itx *CommandInteraction) DeleteReply() error {
-	_, err := itx.BaseClient.Rest.Request(http.MethodDelete, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token+"/messages/@original", nil)
-	return err
-}
-
-func (itx *CommandInteraction) SendFollowUp(content ResponseMessageData, ephemeral bool) (Message, error) {
-	if ephemeral {
-		content.Flags |= EPHEMERAL_MESSAGE_FLAG
-	}
-
-	raw, err := itx.BaseClient.Rest.Request(http.MethodPost, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token, content)
-	if err != nil {
-		return Message{}, err
+// Returns text typed into focused option. Discord sends partial input of number options as text too, so it works for them as well.
+func (itx *CommandInteraction) FocusedString() (string, bool) {
+	option, ok := itx.FocusedOption()
+	if !ok {
+		return "", false
 	}
 
-	res := Message{}
-	err = json.Unmarshal(raw, &res)
-	if err != nil {
-		return Message{}, errors.New("failed to parse received data from discord")
+	switch value := option.Value.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
 	}
-
-	return res, nil
-}
-
-func (itx *CommandInteraction) SendLinearFollowUp(content string, ephemeral bool) (Message, error) {
-	return itx.SendFollowUp(ResponseMessageData{
-		Content: content,
-	}, ephemeral)
+	return "", false
 }
 
-func (itx *CommandInteraction) SendFollowUpWithFiles(content ResponseMessageData, ephemeral bool, files []File) (Message, error) {
-	if ephemeral {
-		content.Flags |= EPHEMERAL_MESSAGE_FLAG
+// Returns value of focused integer option. It fails when user typed something that isn't (yet) a valid integer.
+func (itx *CommandInteraction) FocusedInteger() (int64, bool) {
+	option, ok := itx.FocusedOption()
+	if !ok {
+		return 0, false
 	}
 
-	raw, err := itx.BaseClient.Rest.RequestWithFiles(http.MethodPost, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token, content, files)
-	if err != nil {
-		return Message{}, err
+	switch value := option.Value.(type) {
+	case float64:
+		return int64(value), value == math.Trunc(value)
+	case string:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return parsed, err == nil
 	}
-
-	res := Message{}
-	err = json.Unmarshal(raw, &res)
-	if err != nil {
-		return Message{}, errors.New("failed to parse received data from discord")
-	}
-
-	return res, nil
-}
-
-func (itx *CommandInteraction) SendLinearFollowUpWithFiles(content string, ephemeral bool, files []File) (Message, error) {
-	return itx.SendFollowUpWithFiles(ResponseMessageData{
-		Content: content,
-	}, ephemeral, files)
-}
-
-func (itx *CommandInteraction) EditFollowUp(messageID Snowflake, content ResponseMessageData) error {
-	_, err := itx.BaseClient.Rest.Request(http.MethodPatch, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token+"/messages/"+messageID.String(), content)
-	return err
-}
-
-func (itx *CommandInteraction) EditLinearFollowUp(messageID Snowflake, content string) error {
-	return itx.EditFollowUp(messageID, ResponseMessageData{
-		Content: content,
-	})
-}
-
-func (itx *CommandInteraction) DeleteFollowUp(messageID Snowflake) error {
-	_, err := itx.BaseClient.Rest.Request(http.MethodDelete, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token+"/messages/"+messageID.String(), nil)
-	return err
+	return 0, false
 }
 
-// Warning! This method is only for handling auto complete interaction which is a part of command logic.
-// Returns option name and its value of triggered option. Option name is always of string type but you'll need to check type of value.
-func (itx *CommandInteraction) GetFocusedValue() (string, any) {
-	for _, option := range itx.Data.Options {
-		if option.Focused {
-			return option.Name, option.Value
-		}
+// Returns value of focused number option. It fails when user typed something that isn't (yet) a valid number.
+func (itx *CommandInteraction) FocusedNumber() (float64, bool) {
+	option, ok := itx.FocusedOption()
+	if !ok {
+		return 0, false
 	}
 
-	panic("auto complete interaction had no option with \"focused\" field. This error should never happen with correctly defined slash command")
-}
-
-// Sends to discord info that this component was handled successfully without sending anything more.
-func (itx *ComponentInteraction) Acknowledge() error {
-	return itx.responder(Response{
-		Type: DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE,
-	})
-}
-
-func (itx *ComponentInteraction) AcknowledgeWithMessage(reply ResponseMessageData, ephemeral bool) error {
-	if ephemeral {
-		reply.Flags |= EPHEMERAL_MESSAGE_FLAG
+	switch value := option.Value.(type) {
+	case float64:
+		return value, true
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return parsed, err == nil
 	}
-
-	return itx.responder(Response{
-		Type: CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
-		Data: &reply,
-	})
-}
-
-func (itx *ComponentInteraction) AcknowledgeWithLinearMessage(content string, ephemeral bool) error {
-	return itx.AcknowledgeWithMessage(ResponseMessageData{
-		Content: content,
-	}, ephemeral)
-}
-
-func (itx *ComponentInteraction) AcknowledgeWithModal(modal ResponseModalData) error {
-	return itx.responder(Response{
-		Type: MODAL_RESPONSE_TYPE,
-		Data: &modal,
-	})
+	return 0, false
 }
 
 // GetInputValue retrieves the contents of the first [TextInputComponent] inside the modal (at any depth) with the given customID.
@@ -337,87 +213,3 @@ func (itx *ModalInteraction) GetInputValue(customID string) string {
 
 	return ""
 }
-
-// Sends to discord info that this component was handled successfully without sending anything more.
-func (itx *ModalInteraction) Acknowledge() error {
-	return itx.responder(Response{
-		Type: DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE,
-	})
-}
-
-func (itx *ModalInteraction) AcknowledgeWithMessage(response ResponseMessageData, ephemeral bool) error {
-	if ephemeral {
-		response.Flags |= EPHEMERAL_MESSAGE_FLAG
-	}
-
-	return itx.responder(Response{
-		Type: CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
-		Data: &response,
-	})
-}
-
-func (itx *ModalInteraction) AcknowledgeWithLinearMessage(content string, ephemeral bool) error {
-	return itx.AcknowledgeWithMessage(ResponseMessageData{
-		Content: content,
-	}, ephemeral)
-}
-
-func (itx *ModalInteraction) AcknowledgeWithModal(modal ResponseModalData) error {
-	return itx.responder(Response{
-		Type: MODAL_RESPONSE_TYPE,
-		Data: &modal,
-	})
-}
-
-// Used to let user/member know that the bot is processing the modal submission,
-// which will close the modal dialog.
-// Set ephemeral = true to make notification visible only to the submitter.
-func (itx *ModalInteraction) Defer(ephemeral bool) error {
-	var flags MessageFlags = 0
-
-	if ephemeral {
-		flags = EPHEMERAL_MESSAGE_FLAG
-	}
-
-	_, err := itx.BaseClient.Rest.Request(http.MethodPost, "/interactions/"+itx.ID.String()+"/"+itx.Token+"/callback", ResponseMessage{
-		Type: DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
-		Data: &ResponseMessageData{
-			Flags: flags,
-		},
-	})
-
-	if err == nil {
-		itx.deferred = true
-	}
-
-	return err
-}
-
-// Used after defering a modal submission to send a message to the user/member.
-// Set ephemeral = true to make message visible only to the submitter.
-func (itx *ModalInteraction) SendFollowUp(content ResponseMessageData, ephemeral bool) (Message, error) {
-	if ephemeral {
-		content.Flags |= EPHEMERAL_MESSAGE_FLAG
-	}
-
-	raw, err := itx.BaseClient.Rest.Request(http.MethodPost, "/webhooks/"+itx.ApplicationID.String()+"/"+itx.Token, content)
-	if err != nil {
-		return Message{}, err
-	}
-
-	res := Message{}
-	err = json.Unmarshal(raw, &res)
-	if err != nil {
-		return Message{}, errors.New("failed to parse received data from discord")
-	}
-
-	return res, nil
-}
-
-// Used after defering a modal submission to send a message to the user/member.
-// Set ephemeral = true to make message visible only to the submitter.
-func (itx *ModalInteraction) SendLinearFollowUp(content string, ephemeral bool) (Message, error) {
-	return itx.SendFollowUp(ResponseMessageData{
-		Content: content,
-	}, ephemeral)
-}
@@ -0,0 +1,352 @@
+package tempest
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	paginatorCustomIDPrefix = "tempest-paginator"
+	confirmCustomIDPrefix   = "tempest-confirm"
+
+	defaultPaginatorTimeout = time.Minute * 5
+	maxSelectMenuOptions    = 25
+)
+
+var ErrConfirmTimeout = errors.New("confirmation prompt timed out without answer") // Returned by Confirm when user didn't answer in time.
+
+// Loads page with given index (starting from 0). Used by PaginateFunc to build pages only when they're needed.
+type PageLoader func(page int) (ResponseMessageData, error)
+
+type PaginatorOptions struct {
+	Timeout         time.Duration // How long buttons stay active (counted since last page change). By default: 5 minutes. Keep it below 15 minutes - after that interaction token expires and buttons can't be disabled.
+	StartPage       int           // Index of initially displayed page.
+	Ephemeral       bool          // Whether to send pages as ephemeral message.
+	PageSelect      bool          // Whether to add select menu for jumping to any page (it shows up to 25 pages around current one).
+	AllowEveryone   bool          // Whether other users can use buttons too. By default, only user who invoked interaction can change pages.
+	NotOwnerReply   string        // Ephemeral message sent to users who aren't allowed to use buttons.
+	FirstLabel      string        // By default: "«".
+	PreviousLabel   string        // By default: "‹".
+	NextLabel       string        // By default: "›".
+	LastLabel       string        // By default: "»".
+	HideFirstLast   bool          // Whether to only show previous & next buttons.
+	HideIndicator   bool          // Whether to hide disabled "page/total" button.
+	RemoveOnTimeout bool          // Whether to remove navigation (instead of disabling it) after timeout.
+}
+
+// Replies with first page (or opt.StartPage) and lets user browse through all pages with buttons.
+// It returns right after sending reply, buttons are handled in background (with BaseClient.AwaitComponent) until timeout.
+//
+// Navigation is appended to each page as new action row. For components v2 messages (with IS_COMPONENTS_V2_MESSAGE_FLAG)
+// that end with container - navigation is placed inside that container.
+func Paginate(itx *Interaction, pages []ResponseMessageData, opt PaginatorOptions) error {
+	if len(pages) == 0 {
+		return errors.New("paginator requires at least one page")
+	}
+
+	return PaginateFunc(itx, len(pages), func(page int) (ResponseMessageData, error) {
+		return pages[page], nil
+	}, opt)
+}
+
+// Works like Paginate, but loads pages on demand. Use it for large or expensive to build lists.
+func PaginateFunc(itx *Interaction, pageCount int, loader PageLoader, opt PaginatorOptions) error {
+	if pageCount <= 0 {
+		return errors.New("paginator requires at least one page")
+	}
+
+	if opt.StartPage < 0 || opt.StartPage >= pageCount {
+		return errors.New("paginator start page is out of range")
+	}
+
+	if opt.Timeout <= 0 {
+		opt.Timeout = defaultPaginatorTimeout
+	}
+
+	p := &paginator{
+		itx:     itx,
+		loader:  loader,
+		opt:     opt,
+		count:   pageCount,
+		current: opt.StartPage,
+		prefix:  paginatorCustomIDPrefix + ROUTE_SEGMENT_SEPARATOR + itx.ID.String() + ROUTE_SEGMENT_SEPARATOR,
+		ownerID: itx.BaseUser().ID,
+	}
+
+	content, err := p.render(false)
+	if err != nil {
+		return err
+	}
+
+	if pageCount == 1 {
+		return itx.SendReply(content, opt.Ephemeral, nil)
+	}
+
+	if err := itx.BaseClient.AwaitComponent(p.customIDs(), opt.Timeout, p.onAction, p.onTimeout); err != nil {
+		return err
+	}
+
+	if err := itx.SendReply(content, opt.Ephemeral, nil); err != nil {
+		itx.BaseClient.cancelComponentListeners(p.customIDs()...)
+		return err
+	}
+
+	return nil
+}
+
+type paginator struct {
+	itx     *Interaction
+	loader  PageLoader
+	prefix  string
+	opt     PaginatorOptions
+	count   int
+	current int
+	ownerID Snowflake
+	mu      sync.Mutex
+}
+
+func (p *paginator) customIDs() []string {
+	ids := []string{p.prefix + "first", p.prefix + "prev", p.prefix + "next", p.prefix + "last"}
+	if p.opt.PageSelect {
+		ids = append(ids, p.prefix+"select")
+	}
+	return ids
+}
+
+func (p *paginator) onAction(itx *ComponentInteraction) bool {
+	if !p.opt.AllowEveryone && itx.BaseUser().ID != p.ownerID {
+		itx.SendLinearFollowUp(labelOr(p.opt.NotOwnerReply, "Only the person who opened these pages can use the buttons."), true)
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	target := p.current
+	switch itx.Data.CustomID[len(p.prefix):] {
+	case "first":
+		target = 0
+	case "prev":
+		target--
+	case "next":
+		target++
+	case "last":
+		target = p.count - 1
+	case "select":
+		if len(itx.Data.Values) != 0 {
+			if page, err := strconv.Atoi(itx.Data.Values[0]); err == nil {
+				target = page
+			}
+		}
+	}
+
+	target = max(0, min(target, p.count-1))
+	previous := p.current
+	p.current = target
+
+	content, err := p.render(false)
+	if err != nil {
+		p.current = previous
+		p.itx.BaseClient.handleError(itx.Interaction, err)
+		return false
+	}
+
+	if err := itx.UpdateMessage(content, nil); err != nil {
+		p.itx.BaseClient.tracef("Paginator failed to update message: %v", err)
+		return false
+	}
+
+	// Extend listener, so timeout counts from the last page change.
+	p.itx.BaseClient.ExtendComponentListener(itx.Data.CustomID, p.opt.Timeout)
+	return false
+}
+
+func (p *paginator) onTimeout() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	content, err := p.render(true)
+	if err != nil {
+		p.itx.BaseClient.tracef("Paginator failed to render page after timeout: %v", err)
+		return
+	}
+
+	if err := p.itx.EditReply(content, p.opt.Ephemeral); err != nil {
+		p.itx.BaseClient.tracef("Paginator failed to disable navigation: %v", err)
+	}
+}
+
+func (p *paginator) render(expired bool) (ResponseMessageData, error) {
+	content, err := p.loader(p.current)
+	if err != nil {
+		return content, err
+	}
+
+	if expired && p.opt.RemoveOnTimeout || p.count == 1 {
+		return content, nil
+	}
+
+	return appendNavigation(content, p.navigation(expired)), nil
+}
+
+func (p *paginator) navigation(disabled bool) []ActionRowComponent {
+	button := func(id string, label string, off bool) ButtonComponent {
+		return ButtonComponent{
+			Type:     BUTTON_COMPONENT_TYPE,
+			Style:    SECONDARY_BUTTON_STYLE,
+			CustomID: p.prefix + id,
+			Label:    label,
+			Disabled: disabled || off,
+		}
+	}
+
+	atStart, atEnd := p.current == 0, p.current == p.count-1
+	buttons := make([]ActionRowChildComponent, 0, 5)
+	if !p.opt.HideFirstLast {
+		buttons = append(buttons, button("first", labelOr(p.opt.FirstLabel, "«"), atStart))
+	}
+
+	buttons = append(buttons, button("prev", labelOr(p.opt.PreviousLabel, "‹"), atStart))
+	if !p.opt.HideIndicator {
+		buttons = append(buttons, button("page", strconv.Itoa(p.current+1)+"/"+strconv.Itoa(p.count), true))
+	}
+
+	buttons = append(buttons, button("next", labelOr(p.opt.NextLabel, "›"), atEnd))
+	if !p.opt.HideFirstLast {
+		buttons = append(buttons, button("last", labelOr(p.opt.LastLabel, "»"), atEnd))
+	}
+
+	rows := []ActionRowComponent{{Type: ACTION_ROW_COMPONENT_TYPE, Components: buttons}}
+	if !p.opt.PageSelect {
+		return rows
+	}
+
+	// Discord limits select menus to 25 options, so larger paginators show window around current page.
+	start := max(0, min(p.current-maxSelectMenuOptions/2, p.count-maxSelectMenuOptions))
+	end := min(p.count, start+maxSelectMenuOptions)
+	options := make([]SelectMenuOption, 0, end-start)
+	for i := start; i < end; i++ {
+		options = append(options, SelectMenuOption{
+			Label:   "Page " + strconv.Itoa(i+1),
+			Value:   strconv.Itoa(i),
+			Default: i == p.current,
+		})
+	}
+
+	return append(rows, ActionRowComponent{
+		Type: ACTION_ROW_COMPONENT_TYPE,
+		Components: []ActionRowChildComponent{StringSelectComponent{
+			Type:        STRING_SELECT_COMPONENT_TYPE,
+			CustomID:    p.prefix + "select",
+			Placeholder: "Jump to page",
+			Options:     options,
+			Disabled:    disabled,
+		}},
+	})
+}
+
+// Asks user to confirm (or cancel) action and blocks until they answer or timeout passes.
+// Only user who invoked interaction can answer. Once answered, buttons get disabled and user's choice is highlighted.
+//
+// Prompt works with both classic and components v2 messages (see Paginate for placement of buttons).
+// Returns ErrConfirmTimeout when user didn't answer in time.
+func Confirm(itx *Interaction, prompt ResponseMessageData, timeout time.Duration) (bool, error) {
+	prefix := confirmCustomIDPrefix + ROUTE_SEGMENT_SEPARATOR + itx.ID.String() + ROUTE_SEGMENT_SEPARATOR
+	ownerID := itx.BaseUser().ID
+
+	buttons := func(disabled bool, answer string) []ActionRowComponent {
+		confirmStyle, cancelStyle := SUCCESS_BUTTON_STYLE, DANGER_BUTTON_STYLE
+		switch answer {
+		case "yes":
+			cancelStyle = SECONDARY_BUTTON_STYLE
+		case "no":
+			confirmStyle = SECONDARY_BUTTON_STYLE
+		case "":
+			if disabled {
+				confirmStyle, cancelStyle = SECONDARY_BUTTON_STYLE, SECONDARY_BUTTON_STYLE
+			}
+		}
+
+		return []ActionRowComponent{{
+			Type: ACTION_ROW_COMPONENT_TYPE,
+			Components: []ActionRowChildComponent{
+				ButtonComponent{Type: BUTTON_COMPONENT_TYPE, Style: confirmStyle, CustomID: prefix + "yes", Label: "Confirm", Disabled: disabled},
+				ButtonComponent{Type: BUTTON_COMPONENT_TYPE, Style: cancelStyle, CustomID: prefix + "no", Label: "Cancel", Disabled: disabled},
+			},
+		}}
+	}
+
+	// Answer and timeout may race (double clicks, sweeper), so only the first one settles prompt.
+	answers := make(chan bool, 1)
+	var settle sync.Once
+	err := itx.BaseClient.AwaitComponent([]string{prefix + "yes", prefix + "no"}, timeout, func(citx *ComponentInteraction) bool {
+		if citx.BaseUser().ID != ownerID {
+			citx.SendLinearFollowUp("Only the person who started this action can answer.", true)
+			return false
+		}
+
+		answer := citx.Data.CustomID[len(prefix):]
+		if err := citx.UpdateMessage(appendNavigation(prompt, buttons(true, answer)), nil); err != nil {
+			citx.BaseClient.tracef("Confirmation prompt failed to disable buttons: %v", err)
+		}
+
+		settle.Do(func() { answers <- answer == "yes" })
+		return true
+	}, func() {
+		if err := itx.EditReply(appendNavigation(prompt, buttons(true, "")), false); err != nil {
+			itx.BaseClient.tracef("Confirmation prompt failed to disable buttons: %v", err)
+		}
+		settle.Do(func() { close(answers) })
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := itx.SendReply(appendNavigation(prompt, buttons(false, "")), false, nil); err != nil {
+		itx.BaseClient.cancelComponentListeners(prefix + "yes")
+		return false, err
+	}
+
+	answer, ok := <-answers
+	if !ok {
+		return false, ErrConfirmTimeout
+	}
+	return answer, nil
+}
+
+// Returns copy of message with extra action rows. Rows go inside trailing container of components v2 messages.
+func appendNavigation(content ResponseMessageData, rows []ActionRowComponent) ResponseMessageData {
+	if content.Flags&IS_COMPONENTS_V2_MESSAGE_FLAG != 0 && len(content.Components) != 0 {
+		if container, ok := content.Components[len(content.Components)-1].(ContainerComponent); ok {
+			children := make([]ContainerChildComponent, len(container.Components), len(container.Components)+len(rows))
+			copy(children, container.Components)
+			for _, row := range rows {
+				children = append(children, row)
+			}
+			container.Components = children
+
+			components := make([]MessageComponent, len(content.Components))
+			copy(components, content.Components)
+			components[len(components)-1] = container
+			content.Components = components
+			return content
+		}
+	}
+
+	components := make([]MessageComponent, len(content.Components), len(content.Components)+len(rows))
+	copy(components, content.Components)
+	for _, row := range rows {
+		components = append(components, row)
+	}
+	content.Components = components
+	return content
+}
+
+func labelOr(label string, fallback string) string {
+	if label == "" {
+		return fallback
+	}
+	return label
+}
@@ -0,0 +1,85 @@
+package tempest
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestAppendNavigation(t *testing.T) {
+	rows := []ActionRowComponent{{Type: ACTION_ROW_COMPONENT_TYPE}}
+
+	classic := ResponseMessageData{Components: []MessageComponent{TextDisplayComponent{Type: TEXT_DISPLAY_COMPONENT_TYPE, Content: "Page"}}}
+	result := appendNavigation(classic, rows)
+	if len(result.Components) != 2 || len(classic.Components) != 1 {
+		t.Fatalf("expected navigation appended as new top level row, got %d components (input has %d)", len(result.Components), len(classic.Components))
+	}
+
+	if _, ok := result.Components[1].(ActionRowComponent); !ok {
+		t.Errorf("expected trailing action row, got %T", result.Components[1])
+	}
+
+	container := ContainerComponent{
+		Type:       CONTAINER_COMPONENT_TYPE,
+		Components: []ContainerChildComponent{TextDisplayComponent{Type: TEXT_DISPLAY_COMPONENT_TYPE, Content: "Page"}},
+	}
+	v2 := ResponseMessageData{
+		Flags:      IS_COMPONENTS_V2_MESSAGE_FLAG,
+		Components: []MessageComponent{TextDisplayComponent{Type: TEXT_DISPLAY_COMPONENT_TYPE, Content: "Header"}, container},
+	}
+
+	result = appendNavigation(v2, rows)
+	if len(result.Components) != 2 {
+		t.Fatalf("expected navigation inside trailing container, got %d top level components", len(result.Components))
+	}
+
+	got, ok := result.Components[1].(ContainerComponent)
+	if !ok || len(got.Components) != 2 {
+		t.Fatalf("expected container with page & navigation, got %+v", result.Components[1])
+	}
+
+	if _, ok := got.Components[1].(ActionRowComponent); !ok {
+		t.Errorf("expected action row at the end of container, got %T", got.Components[1])
+	}
+
+	if original := v2.Components[1].(ContainerComponent); len(original.Components) != 1 {
+		t.Errorf("input container got mutated: %+v", original)
+	}
+}
+
+func TestPaginatorSelectWindow(t *testing.T) {
+	cases := []struct {
+		count, current int
+		first, last    int
+	}{
+		{count: 10, current: 4, first: 0, last: 9},
+		{count: 100, current: 0, first: 0, last: 24},
+		{count: 100, current: 50, first: 38, last: 62},
+		{count: 100, current: 99, first: 75, last: 99},
+	}
+
+	for _, tc := range cases {
+		p := &paginator{prefix: "p:", count: tc.count, current: tc.current, opt: PaginatorOptions{PageSelect: true}}
+		rows := p.navigation(false)
+		if len(rows) != 2 {
+			t.Fatalf("expected buttons & select rows, got %d", len(rows))
+		}
+
+		menu := rows[1].Components[0].(StringSelectComponent)
+		options := menu.Options
+		if options[0].Value != strconv.Itoa(tc.first) || options[len(options)-1].Value != strconv.Itoa(tc.last) {
+			t.Errorf("count = %d, current = %d: expected pages %d-%d, got %s-%s", tc.count, tc.current, tc.first, tc.last, options[0].Value, options[len(options)-1].Value)
+		}
+
+		for _, option := range options {
+			if option.Default != (option.Value == strconv.Itoa(tc.current)) {
+				t.Errorf("count = %d, current = %d: unexpected default option %s", tc.count, tc.current, option.Value)
+			}
+		}
+	}
+
+	p := &paginator{prefix: "p:", count: 3, current: 0, opt: PaginatorOptions{HideFirstLast: true}}
+	buttons := p.navigation(false)[0].Components
+	if len(buttons) != 3 || !buttons[0].(ButtonComponent).Disabled || buttons[2].(ButtonComponent).Disabled {
+		t.Errorf("expected prev (disabled), indicator & next buttons on first page, got %+v", buttons)
+	}
+}
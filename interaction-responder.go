@@ -0,0 +1,355 @@
+package tempest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Returns whether Discord accepts provided response type as initial response to interaction of given type.
+//
+// https://docs.discord.com/developers/interactions/receiving-and-responding#interaction-response-object-interaction-callback-type
+func ResponseTypeAllowed(itxType InteractionType, resType ResponseType) bool {
+	switch itxType {
+	case PING_INTERACTION_TYPE:
+		return resType == PONG_RESPONSE_TYPE
+	case APPLICATION_COMMAND_INTERACTION_TYPE:
+		return resType == CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE ||
+			resType == DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE ||
+			resType == MODAL_RESPONSE_TYPE ||
+			resType == LAUNCH_ACTIVITY_RESPONSE_TYPE
+	case MESSAGE_COMPONENT_INTERACTION_TYPE:
+		return resType == CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE ||
+			resType == DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE ||
+			resType == DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE ||
+			resType == UPDATE_MESSAGE_RESPONSE_TYPE ||
+			resType == MODAL_RESPONSE_TYPE ||
+			resType == LAUNCH_ACTIVITY_RESPONSE_TYPE
+	case APPLICATION_COMMAND_AUTO_COMPLETE_INTERACTION_TYPE:
+		return resType == AUTOCOMPLETE_RESPONSE_TYPE
+	case MODAL_SUBMIT_INTERACTION_TYPE:
+		// Update types are only valid when modal was opened from a component, but there's no way to tell that from modal submit itself.
+		return resType == CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE ||
+			resType == DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE ||
+			resType == DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE ||
+			resType == UPDATE_MESSAGE_RESPONSE_TYPE
+	}
+
+	return false
+}
+
+// Returns whether this interaction was deferred (with either Defer or DeferUpdate) and still waits for the final response.
+func (itx *Interaction) Deferred() bool {
+	_, deferred, _ := itx.state()
+	return deferred
+}
+
+// Returns snapshot of response state. Deferred is only reported until interaction gets final response.
+func (itx *Interaction) state() (responded bool, deferred bool, deferredUpdate bool) {
+	itx.mu.Lock()
+	defer itx.mu.Unlock()
+	return itx.responded, itx.deferred && !itx.responded, itx.deferredUpdate && !itx.responded
+}
+
+// Returns whether client automatically deferred this interaction with "thinking" message visible to everyone.
+func (itx *Interaction) publicAutoDeferred() bool {
+	itx.mu.Lock()
+	defer itx.mu.Unlock()
+	return itx.autoDeferred && itx.autoDeferPublic
+}
+
+func (itx *Interaction) markResponded() {
+	itx.mu.Lock()
+	itx.responded = true
+	itx.mu.Unlock()
+}
+
+// Sends initial response to Discord. All response helpers end up here - it validates response type against
+// interaction type and keeps track of interaction state, so use it only if none of the helpers fit your needs.
+func (itx *Interaction) Respond(res Response) error {
+	if !ResponseTypeAllowed(itx.Type, res.Type) {
+		return fmt.Errorf("response type %d is not allowed for interaction type %d", res.Type, itx.Type)
+	}
+
+	itx.mu.Lock()
+	defer itx.mu.Unlock()
+
+	if itx.autoDeferred && !itx.responded {
+		return itx.respondAfterAutoDeferLocked(res)
+	}
+
+	if itx.responded || itx.deferred {
+		return errors.New("interaction has already been responded to or deferred")
+	}
+
+	if err := itx.responder(res); err != nil {
+		return err
+	}
+
+	if itx.autoDeferTimer != nil {
+		itx.autoDeferTimer.Stop()
+	}
+
+	switch res.Type {
+	case DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE:
+		itx.deferred = true
+	case DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE:
+		itx.deferred = true
+		itx.deferredUpdate = true
+	default:
+		itx.responded = true
+	}
+
+	return nil
+}
+
+func (itx *Interaction) webhookEndpoint() string {
+	return "/webhooks/" + itx.ApplicationID.String() + "/" + itx.Token
+}
+
+// Use to let user/member know that bot is processing interaction ("Bot is thinking..." message).
+// Make ephemeral = true to make notification visible only to target.
+// Later call SendReply (or EditReply) to replace loading message with the actual response.
+func (itx *Interaction) Defer(ephemeral bool) error {
+	var flags MessageFlags = 0
+	if ephemeral {
+		flags = EPHEMERAL_MESSAGE_FLAG
+	}
+
+	return itx.Respond(Response{
+		Type: DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
+		Data: &ResponseMessageData{Flags: flags},
+	})
+}
+
+// Acknowledges component (or modal opened from component) interaction without showing loading state to user.
+// Later call UpdateMessage (or EditReply) to edit message that component is attached to.
+func (itx *Interaction) DeferUpdate() error {
+	return itx.Respond(Response{
+		Type: DEFERRED_UPDATE_MESSAGE_RESPONSE_TYPE,
+	})
+}
+
+// Acknowledges the interaction with a message. Set ephemeral = true to make message visible only to target.
+//
+// If interaction was deferred with Defer - it replaces loading message.
+// If interaction was deferred with DeferUpdate - it sends new follow-up message (so component's message is kept untouched).
+func (itx *Interaction) SendReply(reply ResponseMessageData, ephemeral bool, files []File) error {
+	if ephemeral {
+		reply.Flags |= EPHEMERAL_MESSAGE_FLAG
+	}
+
+	responded, deferred, deferredUpdate := itx.state()
+	if responded {
+		return errors.New("interaction has already been responded to")
+	}
+
+	if !deferred && len(files) > 0 {
+		err := itx.Respond(Response{
+			Type: DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
+			Data: &ResponseMessageData{Flags: reply.Flags},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, deferred, deferredUpdate = itx.state()
+	}
+
+	if deferredUpdate {
+		_, err := itx.SendFollowUpWithFiles(reply, ephemeral, files)
+		if err == nil {
+			itx.markResponded()
+		}
+		return err
+	}
+
+	if deferred {
+		var err error
+		if reply.Flags&EPHEMERAL_MESSAGE_FLAG != 0 && itx.publicAutoDeferred() {
+			err = itx.replacePublicAutoDefer(reply, files)
+		} else {
+			_, err = itx.BaseClient.Rest.RequestWithFilesLimit(http.MethodPatch, itx.webhookEndpoint()+"/messages/@original", reply, files, itx.AttachmentSizeLimit)
+		}
+
+		if err == nil {
+			itx.markResponded()
+		}
+		return err
+	}
+
+	return itx.Respond(Response{
+		Type: CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
+		Data: &reply,
+	})
+}
+
+func (itx *Interaction) SendLinearReply(content string, ephemeral bool) error {
+	return itx.SendReply(ResponseMessageData{
+		Content: content,
+	}, ephemeral, nil)
+}
+
+// Edits message that component is attached to in a single round-trip. Only valid for component interactions
+// (and modals opened from component). If interaction was deferred - it edits original message instead.
+func (itx *Interaction) UpdateMessage(content ResponseMessageData, files []File) error {
+	responded, deferred, _ := itx.state()
+	if responded {
+		return errors.New("interaction has already been responded to")
+	}
+
+	if !deferred && len(files) > 0 {
+		if err := itx.DeferUpdate(); err != nil {
+			return err
+		}
+		deferred = true
+	}
+
+	if deferred {
+		_, err := itx.BaseClient.Rest.RequestWithFilesLimit(http.MethodPatch, itx.webhookEndpoint()+"/messages/@original", content, files, itx.AttachmentSizeLimit)
+		if err == nil {
+			itx.markResponded()
+		}
+		return err
+	}
+
+	return itx.Respond(Response{
+		Type: UPDATE_MESSAGE_RESPONSE_TYPE,
+		Data: &content,
+	})
+}
+
+func (itx *Interaction) UpdateLinearMessage(content string) error {
+	return itx.UpdateMessage(ResponseMessageData{
+		Content: content,
+	}, nil)
+}
+
+// Opens modal for user. It has to be initial response - it cannot follow Defer or DeferUpdate.
+// Not available for auto complete & modal submit interactions.
+func (itx *Interaction) SendModal(modal ResponseModalData) error {
+	return itx.Respond(Response{
+		Type: MODAL_RESPONSE_TYPE,
+		Data: &modal,
+	})
+}
+
+// Edits initial response (or message that component is attached to when interaction was acknowledged with DeferUpdate).
+func (itx *Interaction) EditReply(content ResponseMessageData, ephemeral bool) error {
+	if ephemeral {
+		content.Flags |= EPHEMERAL_MESSAGE_FLAG
+	}
+
+	_, err := itx.BaseClient.Rest.Request(http.MethodPatch, itx.webhookEndpoint()+"/messages/@original", content)
+	if _, deferred, _ := itx.state(); err == nil && deferred {
+		itx.markResponded()
+	}
+	return err
+}
+
+func (itx *Interaction) EditLinearReply(content string, ephemeral bool) error {
+	return itx.EditReply(ResponseMessageData{
+		Content: content,
+	}, ephemeral)
+}
+
+func (itx *Interaction) DeleteReply() error {
+	_, err := itx.BaseClient.Rest.Request(http.MethodDelete, itx.webhookEndpoint()+"/messages/@original", nil)
+	return err
+}
+
+// Returns initial response message (or message that component is attached to when interaction was acknowledged with DeferUpdate).
+func (itx *Interaction) FetchReply() (Message, error) {
+	raw, err := itx.BaseClient.Rest.Request(http.MethodGet, itx.webhookEndpoint()+"/messages/@original", nil)
+	if err != nil {
+		return Message{}, err
+	}
+
+	res := Message{}
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return Message{}, errors.New("failed to parse received data from discord")
+	}
+
+	return res, nil
+}
+
+func (itx *Interaction) SendFollowUp(content ResponseMessageData, ephemeral bool) (Message, error) {
+	return itx.SendFollowUpWithFiles(content, ephemeral, nil)
+}
+
+func (itx *Interaction) SendLinearFollowUp(content string, ephemeral bool) (Message, error) {
+	return itx.SendFollowUp(ResponseMessageData{
+		Content: content,
+	}, ephemeral)
+}
+
+func (itx *Interaction) SendFollowUpWithFiles(content ResponseMessageData, ephemeral bool, files []File) (Message, error) {
+	if ephemeral {
+		content.Flags |= EPHEMERAL_MESSAGE_FLAG
+	}
+
+	raw, err := itx.BaseClient.Rest.RequestWithFilesLimit(http.MethodPost, itx.webhookEndpoint(), content, files, itx.AttachmentSizeLimit)
+	if err != nil {
+		return Message{}, err
+	}
+
+	res := Message{}
+	err = json.Unmarshal(raw, &res)
+	if err != nil {
+		return Message{}, errors.New("failed to parse received data from discord")
+	}
+
+	return res, nil
+}
+
+func (itx *Interaction) SendLinearFollowUpWithFiles(content string, ephemeral bool, files []File) (Message, error) {
+	return itx.SendFollowUpWithFiles(ResponseMessageData{
+		Content: content,
+	}, ephemeral, files)
+}
+
+func (itx *Interaction) EditFollowUp(messageID Snowflake, content ResponseMessageData) error {
+	_, err := itx.BaseClient.Rest.Request(http.MethodPatch, itx.webhookEndpoint()+"/messages/"+messageID.String(), content)
+	return err
+}
+
+func (itx *Interaction) EditLinearFollowUp(messageID Snowflake, content string) error {
+	return itx.EditFollowUp(messageID, ResponseMessageData{
+		Content: content,
+	})
+}
+
+func (itx *Interaction) DeleteFollowUp(messageID Snowflake) error {
+	_, err := itx.BaseClient.Rest.Request(http.MethodDelete, itx.webhookEndpoint()+"/messages/"+messageID.String(), nil)
+	return err
+}
+
+// Sends to discord info that this interaction was handled successfully without sending anything more.
+// It's the same as DeferUpdate, so it's only valid for component & modal interactions.
+func (itx *Interaction) Acknowledge() error {
+	return itx.DeferUpdate()
+}
+
+// Responds with new message. Unlike SendReply, it never edits deferred response.
+func (itx *Interaction) AcknowledgeWithMessage(reply ResponseMessageData, ephemeral bool) error {
+	if ephemeral {
+		reply.Flags |= EPHEMERAL_MESSAGE_FLAG
+	}
+
+	return itx.Respond(Response{
+		Type: CHANNEL_MESSAGE_WITH_SOURCE_RESPONSE_TYPE,
+		Data: &reply,
+	})
+}
+
+func (itx *Interaction) AcknowledgeWithLinearMessage(content string, ephemeral bool) error {
+	return itx.AcknowledgeWithMessage(ResponseMessageData{
+		Content: content,
+	}, ephemeral)
+}
+
+func (itx *Interaction) AcknowledgeWithModal(modal ResponseModalData) error {
+	return itx.SendModal(modal)
+}
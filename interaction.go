@@ -1,6 +1,10 @@
 package tempest
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
 
 // https://docs.discord.com/developers/interactions/receiving-and-responding#interaction-object-interaction-type
 type InteractionType uint8
@@ -38,7 +42,6 @@ type Interaction struct {
 	HTTPClient    *HTTPClient              `json:"-"` // Only provided if using HTTP Client.
 
 	// authorizing_integration_owners or contexts are pointless as they essentially duplicate data you already have :)
-	// attachment_size_limit is also skipped - appears to have no use anywhere
 
 	BaseClient   *BaseClient     `json:"-"` // Always provided.
 	User         *User           `json:"user,omitempty"`
@@ -63,12 +66,22 @@ type Interaction struct {
 
 	// version is skipped (docs says it's always 1, read-only property)
 
+	AttachmentSizeLimit int64             `json:"attachment_size_limit,omitempty"` // Max size (in bytes) of each file that can be attached to response (respects guild boosts & user's Nitro).
+	autoDeferTimer      *time.Timer       `json:"-"`
+	mu                  sync.Mutex        `json:"-"` // Guards response state as auto defer may respond from another goroutine.
+	values              map[string]any    `json:"-"` // Per invocation store shared between middleware & handlers.
+	valuesMu            sync.Mutex        `json:"-"`
+	params              map[string]string `json:"-"` // Params extracted from custom ID by matched route.
+
 	PermissionFlags PermissionFlags `json:"app_permissions,string"` // Bitwise set of permissions the app/bot has within the channel the interaction was sent from (guild text channel or DM channel).
 	ApplicationID   Snowflake       `json:"application_id"`
 	ShardID         uint16          `json:"-"` // Only provided if using Gateway Client. Shard ID = 0 is also a valid ID.
 	Type            InteractionType `json:"type"`
 	responded       bool            `json:"-"`
 	deferred        bool            `json:"-"`
+	deferredUpdate  bool            `json:"-"` // Deferred with DEFERRED_UPDATE_MESSAGE (component's message will be edited instead of new message).
+	autoDeferred    bool            `json:"-"` // Deferred by client because handler didn't respond in time.
+	autoDeferPublic bool            `json:"-"` // Automatically deferred with "thinking" message visible to everyone.
 }
 
 // A CommandInteraction represents an interaction received from a user invoking an application command, such as a slash command or a context menu command.
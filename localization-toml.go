@@ -0,0 +1,201 @@
+package tempest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parses subset of TOML used by message bundles: comments, [tables], (dotted & quoted) keys and single line strings.
+// Result is nested map, just like decoded JSON object.
+func parseTOMLStrings(src string) (map[string]any, error) {
+	root := make(map[string]any)
+	table := root
+
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: arrays of tables are not supported", i+1)
+			}
+
+			keys, rest, err := parseTOMLKey(line[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+
+			if !strings.HasPrefix(rest, "]") || !isTOMLLineEnd(rest[1:]) {
+				return nil, fmt.Errorf("line %d: expected \"]\" after table name", i+1)
+			}
+
+			table, err = tomlTable(root, keys)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		keys, rest, err := parseTOMLKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if !strings.HasPrefix(rest, "=") {
+			return nil, fmt.Errorf("line %d: expected \"=\" after key", i+1)
+		}
+
+		value, rest, err := parseTOMLString(strings.TrimSpace(rest[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if !isTOMLLineEnd(rest) {
+			return nil, fmt.Errorf("line %d: unexpected %q after value", i+1, rest)
+		}
+
+		parent, err := tomlTable(table, keys[:len(keys)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		last := keys[len(keys)-1]
+		if _, exists := parent[last]; exists {
+			return nil, fmt.Errorf("line %d: key %q is defined twice", i+1, strings.Join(keys, "."))
+		}
+		parent[last] = value
+	}
+
+	return root, nil
+}
+
+// Returns (creating if needed) nested table under given keys.
+func tomlTable(table map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			created := make(map[string]any)
+			table[key] = created
+			table = created
+		case map[string]any:
+			table = next
+		default:
+			return nil, fmt.Errorf("key %q is already used by a string", key)
+		}
+	}
+	return table, nil
+}
+
+// Parses dotted key (like `a."b c".d`) and returns its parts with remaining, trimmed text.
+func parseTOMLKey(text string) ([]string, string, error) {
+	var keys []string
+	for {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, "", errors.New("missing key")
+		}
+
+		var key string
+		if text[0] == '"' || text[0] == '\'' {
+			var err error
+			key, text, err = parseTOMLString(text)
+			if err != nil {
+				return nil, "", err
+			}
+		} else {
+			end := strings.IndexFunc(text, func(r rune) bool {
+				return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-')
+			})
+			if end == -1 {
+				end = len(text)
+			}
+
+			if end == 0 {
+				return nil, "", fmt.Errorf("invalid character %q in key", text[0])
+			}
+			key, text = text[:end], text[end:]
+		}
+
+		keys = append(keys, key)
+		text = strings.TrimSpace(text)
+		if !strings.HasPrefix(text, ".") {
+			return keys, text, nil
+		}
+		text = text[1:]
+	}
+}
+
+// Parses basic ("...") or literal ('...') string at the start of text and returns it with remaining text.
+func parseTOMLString(text string) (string, string, error) {
+	if text == "" || (text[0] != '"' && text[0] != '\'') {
+		return "", "", errors.New("expected string value (only strings are supported)")
+	}
+
+	if strings.HasPrefix(text, `"""`) || strings.HasPrefix(text, "'''") {
+		return "", "", errors.New("multi-line strings are not supported")
+	}
+
+	if text[0] == '\'' {
+		end := strings.IndexByte(text[1:], '\'')
+		if end == -1 {
+			return "", "", errors.New("unterminated string")
+		}
+		return text[1 : end+1], text[end+2:], nil
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '"':
+			return sb.String(), text[i+1:], nil
+		case '\\':
+			if i+1 >= len(text) {
+				return "", "", errors.New("unterminated string")
+			}
+
+			i++
+			switch text[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '"', '\\':
+				sb.WriteByte(text[i])
+			case 'u', 'U':
+				size := 4
+				if text[i] == 'U' {
+					size = 8
+				}
+
+				if i+size >= len(text) {
+					return "", "", errors.New("invalid unicode escape")
+				}
+
+				code, err := strconv.ParseUint(text[i+1:i+1+size], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(code)) {
+					return "", "", errors.New("invalid unicode escape")
+				}
+				sb.WriteRune(rune(code))
+				i += size
+			default:
+				return "", "", fmt.Errorf("unsupported escape sequence \\%c", text[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", "", errors.New("unterminated string")
+}
+
+func isTOMLLineEnd(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || text[0] == '#'
+}
@@ -0,0 +1,525 @@
+package tempest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Plural categories (CLDR names) used as keys of plural messages.
+const (
+	PLURAL_ZERO  = "zero"
+	PLURAL_ONE   = "one"
+	PLURAL_TWO   = "two"
+	PLURAL_FEW   = "few"
+	PLURAL_MANY  = "many"
+	PLURAL_OTHER = "other"
+)
+
+// Name of argument that selects plural form of message (see Catalog.Translate).
+const PLURAL_COUNT_ARG = "count"
+
+type catalogMessage struct {
+	text   string
+	plural map[string]string // Set for plural messages, keyed by plural category.
+}
+
+// Message catalogue with translations of command metadata & responses, grouped by language.
+//
+// Bundles are JSON or TOML documents. Nested objects (or tables) are flattened into dotted keys, so
+// {"ping": {"reply": "Pong!"}} defines "ping.reply" key. Object whose keys are all plural categories
+// defines plural message instead, e.g. {"apples": {"one": "{count} apple", "other": "{count} apples"}}.
+// Messages can use {name} placeholders that are filled in with provided arguments.
+//
+// Commands registered in client with catalog get their localizations filled in automatically, using following keys
+// ("path" is command name, followed by subcommand group & subcommand names, joined with dots; context menu commands
+// use their registry keys instead, like "user:Show avatar"):
+//
+//	commands.<path>.name
+//	commands.<path>.description
+//	commands.<path>.options.<option>.name
+//	commands.<path>.options.<option>.description
+//	commands.<path>.options.<option>.choices.<choice>
+//
+// Localizations provided by hand always take precedence over catalog.
+type Catalog struct {
+	messages        map[Language]map[string]catalogMessage
+	defaultLanguage Language
+	mu              sync.RWMutex
+}
+
+// Creates empty catalog. Default language is the last step of every fallback chain and the reference for MissingKeys.
+// Command names & descriptions are expected to be written in default language, so catalog never overwrites them.
+func NewCatalog(defaultLanguage Language) *Catalog {
+	if defaultLanguage == "" {
+		panic("catalog needs default language")
+	}
+
+	return &Catalog{
+		messages:        make(map[Language]map[string]catalogMessage),
+		defaultLanguage: defaultLanguage,
+	}
+}
+
+func (catalog *Catalog) DefaultLanguage() Language {
+	return catalog.defaultLanguage
+}
+
+// Returns all languages that have at least one message, sorted.
+func (catalog *Catalog) Languages() []Language {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	return slices.Sorted(maps.Keys(catalog.messages))
+}
+
+// Adds (or replaces) single message.
+func (catalog *Catalog) Set(lang Language, key string, text string) {
+	catalog.set(lang, key, catalogMessage{text: text})
+}
+
+// Adds (or replaces) single plural message. Forms are keyed by plural category (see PLURAL_ONE & others).
+func (catalog *Catalog) SetPlural(lang Language, key string, forms map[string]string) error {
+	if len(forms) == 0 {
+		return fmt.Errorf("plural message %q (%s) needs at least one form", key, lang)
+	}
+
+	catalog.set(lang, key, catalogMessage{plural: maps.Clone(forms)})
+	return nil
+}
+
+func (catalog *Catalog) set(lang Language, key string, message catalogMessage) {
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	messages, ok := catalog.messages[lang]
+	if !ok {
+		messages = make(map[string]catalogMessage)
+		catalog.messages[lang] = messages
+	}
+	messages[key] = message
+}
+
+// Loads JSON bundle with messages for given language. Existing keys get replaced.
+func (catalog *Catalog) LoadJSON(lang Language, data []byte) error {
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("failed to parse %s bundle: %w", lang, err)
+	}
+	return catalog.loadTree(lang, tree)
+}
+
+// Loads TOML bundle with messages for given language. Existing keys get replaced.
+// Only subset of TOML is supported: tables, (dotted) keys and single line basic/literal strings.
+func (catalog *Catalog) LoadTOML(lang Language, data []byte) error {
+	tree, err := parseTOMLStrings(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s bundle: %w", lang, err)
+	}
+	return catalog.loadTree(lang, tree)
+}
+
+// Loads every "<locale>.json" and "<locale>.toml" bundle from directory (use "." for root), e.g. "pl.json" or "en-US.toml".
+// Works with os.DirFS and embed.FS. Other files are ignored.
+func (catalog *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		lang := Language(strings.TrimSuffix(entry.Name(), ext))
+		if ext == ".json" {
+			err = catalog.LoadJSON(lang, data)
+		} else {
+			err = catalog.LoadTOML(lang, data)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (catalog *Catalog) loadTree(lang Language, tree map[string]any) error {
+	flat := make(map[string]catalogMessage)
+	if err := flattenCatalogTree(flat, "", tree); err != nil {
+		return fmt.Errorf("invalid %s bundle: %w", lang, err)
+	}
+
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	messages, ok := catalog.messages[lang]
+	if !ok {
+		catalog.messages[lang] = flat
+		return nil
+	}
+	maps.Copy(messages, flat)
+	return nil
+}
+
+func flattenCatalogTree(dst map[string]catalogMessage, prefix string, tree map[string]any) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case string:
+			dst[key] = catalogMessage{text: value}
+		case map[string]any:
+			if forms, ok := pluralForms(value); ok {
+				dst[key] = catalogMessage{plural: forms}
+				continue
+			}
+
+			if err := flattenCatalogTree(dst, key, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("value of %q must be a string or an object", key)
+		}
+	}
+	return nil
+}
+
+// Returns forms of plural message when (non empty) object only holds plural categories.
+func pluralForms(value map[string]any) (map[string]string, bool) {
+	if len(value) == 0 {
+		return nil, false
+	}
+
+	forms := make(map[string]string, len(value))
+	for category, text := range value {
+		str, ok := text.(string)
+		if !ok {
+			return nil, false
+		}
+
+		switch category {
+		case PLURAL_ZERO, PLURAL_ONE, PLURAL_TWO, PLURAL_FEW, PLURAL_MANY, PLURAL_OTHER:
+			forms[category] = str
+		default:
+			return nil, false
+		}
+	}
+	return forms, true
+}
+
+// Finds message in first language (from provided ones, followed by catalog's default language) that has it
+// and fills in {name} placeholders with args. Argument named PLURAL_COUNT_ARG ("count") selects form of plural messages
+// (with "other" form as fallback - language that has neither is skipped). Returns key itself if no language has such message.
+func (catalog *Catalog) Translate(key string, args map[string]any, languages ...Language) string {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	for _, lang := range append(slices.Clip(languages), catalog.defaultLanguage) {
+		if lang == "" {
+			continue
+		}
+
+		message, ok := catalog.messages[lang][key]
+		if !ok {
+			continue
+		}
+
+		text := message.text
+		if message.plural != nil {
+			form, ok := "", false
+			if n, valid := pluralCount(args[PLURAL_COUNT_ARG]); valid {
+				form, ok = message.plural[pluralCategory(lang, n)]
+			}
+
+			if !ok {
+				if form, ok = message.plural[PLURAL_OTHER]; !ok {
+					continue
+				}
+			}
+			text = form
+		}
+
+		return formatCatalogMessage(text, args)
+	}
+
+	return key
+}
+
+// Returns translations of (non plural) message in all languages other than default one.
+// Result is nil when there are none, so it can be assigned directly to localizations field.
+func (catalog *Catalog) Localizations(key string) map[Language]string {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	var localizations map[Language]string
+	for lang, messages := range catalog.messages {
+		message, ok := messages[key]
+		if lang == catalog.defaultLanguage || !ok || message.plural != nil || message.text == "" {
+			continue
+		}
+
+		if localizations == nil {
+			localizations = make(map[Language]string)
+		}
+		localizations[lang] = message.text
+	}
+	return localizations
+}
+
+// Reports keys (sorted) that are missing in each language, compared with all keys known to catalog.
+// Plural messages that lack form required by language's plural rules are reported as "key.category".
+// Languages without missing keys are left out, so empty result means catalog is complete.
+func (catalog *Catalog) MissingKeys() map[Language][]string {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	all := make(map[string]struct{})
+	for _, messages := range catalog.messages {
+		for key := range messages {
+			all[key] = struct{}{}
+		}
+	}
+
+	missing := make(map[Language][]string)
+	for lang, messages := range catalog.messages {
+		for key := range all {
+			message, ok := messages[key]
+			if !ok {
+				missing[lang] = append(missing[lang], key)
+				continue
+			}
+
+			if message.plural == nil {
+				continue
+			}
+
+			for _, category := range pluralCategories(lang) {
+				if _, ok := message.plural[category]; !ok {
+					missing[lang] = append(missing[lang], key+"."+category)
+				}
+			}
+		}
+
+		if keys, ok := missing[lang]; ok {
+			slices.Sort(keys)
+		}
+	}
+	return missing
+}
+
+// Fills in localizations of command (and its options & choices) from catalog. Path is the dotted command path, e.g. "config.logging.channel".
+func (catalog *Catalog) localizeCommand(cmd *Command, path string) {
+	prefix := "commands." + path
+	cmd.NameLocalizations = mergeLocalizations(cmd.NameLocalizations, catalog.Localizations(prefix+".name"))
+	cmd.DescriptionLocalizations = mergeLocalizations(cmd.DescriptionLocalizations, catalog.Localizations(prefix+".description"))
+
+	if len(cmd.Options) == 0 {
+		return
+	}
+
+	options := slices.Clone(cmd.Options)
+	for i := range options {
+		option := &options[i]
+		optionPrefix := prefix + ".options." + option.Name
+		option.NameLocalizations = mergeLocalizations(option.NameLocalizations, catalog.Localizations(optionPrefix+".name"))
+		option.DescriptionLocalizations = mergeLocalizations(option.DescriptionLocalizations, catalog.Localizations(optionPrefix+".description"))
+
+		if len(option.Choices) == 0 {
+			continue
+		}
+
+		option.Choices = slices.Clone(option.Choices)
+		for j := range option.Choices {
+			choice := &option.Choices[j]
+			choice.NameLocalizations = mergeLocalizations(choice.NameLocalizations, catalog.Localizations(optionPrefix+".choices."+choice.Name))
+		}
+	}
+	cmd.Options = options
+}
+
+// Returns copy of manual localizations extended with ones from catalog (manual ones win).
+func mergeLocalizations(manual map[Language]string, fromCatalog map[Language]string) map[Language]string {
+	if len(fromCatalog) == 0 {
+		return manual
+	}
+
+	merged := maps.Clone(fromCatalog)
+	maps.Copy(merged, manual)
+	return merged
+}
+
+func formatCatalogMessage(text string, args map[string]any) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start == -1 {
+			break
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end == -1 {
+			break
+		}
+		end += start
+
+		sb.WriteString(text[:start])
+		if value, ok := args[text[start+1:end]]; ok {
+			fmt.Fprint(&sb, value)
+		} else {
+			sb.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+
+	sb.WriteString(text)
+	return sb.String()
+}
+
+func pluralCount(value any) (int64, bool) {
+	switch n := value.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return int64(n), true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), true
+	case float64:
+		// Fractions use "other" form in every supported language except few edge cases, so they aren't worth separate rules.
+		if n != float64(int64(n)) {
+			return 0, false
+		}
+		return int64(n), true
+	case float32:
+		return pluralCount(float64(n))
+	case string:
+		parsed, err := strconv.ParseInt(n, 10, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+// Returns CLDR plural category of whole number in given language.
+//
+// https://www.unicode.org/cldr/charts/latest/supplemental/language_plural_rules.html
+func pluralCategory(lang Language, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+
+	mod10, mod100 := n%10, n%100
+	switch lang {
+	case CHINESE_CHINA_LANGUAGE, CHINESE_TAIWAN_LANGUAGE, JAPANESE_LANGUAGE, KOREAN_LANGUAGE, THAI_LANGUAGE, VIETNAMESE_LANGUAGE:
+		return PLURAL_OTHER
+	case FRENCH_LANGUAGE, PORTUGUESE_BR_LANGUAGE, HINDI_LANGUAGE:
+		if n == 0 || n == 1 {
+			return PLURAL_ONE
+		}
+	case POLISH_LANGUAGE:
+		switch {
+		case n == 1:
+			return PLURAL_ONE
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PLURAL_FEW
+		}
+		return PLURAL_MANY
+	case RUSSIAN_LANGUAGE, UKRAINIAN_LANGUAGE:
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PLURAL_ONE
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PLURAL_FEW
+		}
+		return PLURAL_MANY
+	case CROATIAN_LANGUAGE:
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PLURAL_ONE
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PLURAL_FEW
+		}
+	case CZECH_LANGUAGE:
+		switch {
+		case n == 1:
+			return PLURAL_ONE
+		case n >= 2 && n <= 4:
+			return PLURAL_FEW
+		}
+	case LITHUANIAN_LANGUAGE:
+		switch {
+		case mod10 == 1 && (mod100 < 11 || mod100 > 19):
+			return PLURAL_ONE
+		case mod10 >= 2 && (mod100 < 11 || mod100 > 19):
+			return PLURAL_FEW
+		}
+	case ROMANIAN_LANGUAGE:
+		switch {
+		case n == 1:
+			return PLURAL_ONE
+		case n == 0 || (mod100 >= 2 && mod100 <= 19):
+			return PLURAL_FEW
+		}
+	default:
+		if n == 1 {
+			return PLURAL_ONE
+		}
+	}
+	return PLURAL_OTHER
+}
+
+// Returns plural categories that whole numbers can fall into in given language.
+func pluralCategories(lang Language) []string {
+	switch lang {
+	case CHINESE_CHINA_LANGUAGE, CHINESE_TAIWAN_LANGUAGE, JAPANESE_LANGUAGE, KOREAN_LANGUAGE, THAI_LANGUAGE, VIETNAMESE_LANGUAGE:
+		return []string{PLURAL_OTHER}
+	case POLISH_LANGUAGE, RUSSIAN_LANGUAGE, UKRAINIAN_LANGUAGE:
+		return []string{PLURAL_ONE, PLURAL_FEW, PLURAL_MANY}
+	case CROATIAN_LANGUAGE, CZECH_LANGUAGE, LITHUANIAN_LANGUAGE, ROMANIAN_LANGUAGE:
+		return []string{PLURAL_ONE, PLURAL_FEW, PLURAL_OTHER}
+	}
+	return []string{PLURAL_ONE, PLURAL_OTHER}
+}
+
+// Translates key with client's Catalog (see BaseClientOptions.Catalog), trying user's language first,
+// then guild's language and finally catalog's default language. Returns key itself when client has no catalog
+// or message is missing in all of them. Use PLURAL_COUNT_ARG ("count") argument to pick plural form.
+func (itx *Interaction) T(key string, args map[string]any) string {
+	if itx.BaseClient == nil || itx.BaseClient.catalog == nil {
+		return key
+	}
+	return itx.BaseClient.catalog.Translate(key, args, itx.Locale, Language(itx.GuildLocale))
+}
@@ -0,0 +1,67 @@
+package tempest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := NewCatalog(ENGLISH_US_LANGUAGE)
+
+	err := catalog.LoadJSON(ENGLISH_US_LANGUAGE, []byte(`{
+		"greeting": "Hello, {name}!",
+		"apples": {"one": "{count} apple", "other": "{count} apples"},
+		"commands": {"ping": {"description": "Checks latency."}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = catalog.LoadTOML(POLISH_LANGUAGE, []byte(`
+# Polish bundle
+greeting = "Cześć, {name}!"
+
+[apples]
+one = "{count} jabłko"
+few = '{count} jabłka'
+
+[commands.ping]
+description = "Sprawdza opóźnienie."
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	translations := []struct {
+		lang     Language
+		key      string
+		args     map[string]any
+		expected string
+	}{
+		{POLISH_LANGUAGE, "greeting", map[string]any{"name": "Ala"}, "Cześć, Ala!"},
+		{GERMAN_LANGUAGE, "greeting", map[string]any{"name": "Ala"}, "Hello, Ala!"},
+		{POLISH_LANGUAGE, "apples", map[string]any{"count": 1}, "1 jabłko"},
+		{POLISH_LANGUAGE, "apples", map[string]any{"count": 22}, "22 jabłka"},
+		{POLISH_LANGUAGE, "apples", map[string]any{"count": 12}, "12 apples"}, // Polish bundle lacks "many" form.
+		{ENGLISH_US_LANGUAGE, "apples", map[string]any{"count": 1}, "1 apple"},
+		{ENGLISH_US_LANGUAGE, "missing", nil, "missing"},
+	}
+
+	for _, tc := range translations {
+		if got := catalog.Translate(tc.key, tc.args, tc.lang); got != tc.expected {
+			t.Errorf("Translate(%q, %s) = %q, expected %q", tc.key, tc.lang, got, tc.expected)
+		}
+	}
+
+	missing := catalog.MissingKeys()
+	expected := map[Language][]string{POLISH_LANGUAGE: {"apples.many"}}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("MissingKeys() = %v, expected %v", missing, expected)
+	}
+
+	cmd := Command{Name: "ping", Description: "Checks latency.", DescriptionLocalizations: map[Language]string{GERMAN_LANGUAGE: "Prüft Latenz."}}
+	catalog.localizeCommand(&cmd, cmd.Name)
+	if cmd.DescriptionLocalizations[POLISH_LANGUAGE] != "Sprawdza opóźnienie." || cmd.DescriptionLocalizations[GERMAN_LANGUAGE] != "Prüft Latenz." || len(cmd.DescriptionLocalizations) != 2 {
+		t.Errorf("unexpected command localizations: %v", cmd.DescriptionLocalizations)
+	}
+}
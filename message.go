@@ -97,6 +97,34 @@ type PartialChannel struct {
 	Type            ChannelType     `json:"type"`
 }
 
+// https://docs.discord.com/developers/resources/channel#channel-object (only thread related fields)
+type ThreadChannel struct {
+	Name             string         `json:"name"`
+	AppliedTags      []Snowflake    `json:"applied_tags,omitzero"` // IDs of the tags that have been applied to a thread in a forum or media channel.
+	ThreadMetadata   ThreadMetadata `json:"thread_metadata"`
+	ID               Snowflake      `json:"id"`
+	GuildID          Snowflake      `json:"guild_id,omitempty"`
+	ParentID         Snowflake      `json:"parent_id,omitempty"` // ID of the text channel this thread was created in.
+	OwnerID          Snowflake      `json:"owner_id,omitempty"`  // ID of the thread creator.
+	LastMessageID    Snowflake      `json:"last_message_id,omitempty"`
+	Flags            BitSet         `json:"flags,omitempty"`
+	MessageCount     uint32         `json:"message_count"`      // Number of messages (not including the initial message or deleted messages) in a thread.
+	MemberCount      uint32         `json:"member_count"`       // An approximate count of users in a thread, stops counting at 50.
+	TotalMessageSent uint32         `json:"total_message_sent"` // Number of messages ever sent in a thread (it won't decrement when messages are deleted).
+	RateLimitPerUser uint16         `json:"rate_limit_per_user,omitempty"`
+	Type             ChannelType    `json:"type"`
+}
+
+// https://docs.discord.com/developers/resources/channel#thread-metadata-object
+type ThreadMetadata struct {
+	ArchiveTimestamp    *time.Time `json:"archive_timestamp"` // Timestamp when the thread's archive status was last changed, used for calculating recent activity.
+	CreateTimestamp     *time.Time `json:"create_timestamp,omitempty"`
+	AutoArchiveDuration uint16     `json:"auto_archive_duration"` // Duration in minutes (60, 1440, 4320 or 10080) after which thread stops showing in the channel list.
+	Archived            bool       `json:"archived"`
+	Locked              bool       `json:"locked"`
+	Invitable           bool       `json:"invitable"` // Whether non-moderators can add other non-moderators to a private thread.
+}
+
 // https://docs.discord.com/developers/resources/message#channel-mention-object
 type ChannelMention struct {
 	Name    string      `json:"name"`
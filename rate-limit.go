@@ -1,6 +1,7 @@
 package tempest
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -12,19 +13,80 @@ import (
 	"time"
 )
 
+// Decides order in which queued requests get their rate limit slots.
+// Slots are shared with weighted round-robin: out of every 7 slots, high priority gets 4, normal gets 2 and low gets 1
+// (unused turns go to the next waiting lane), so background jobs keep moving even under steady traffic.
+// Requests with the same priority are served in FIFO order.
+type RequestPriority uint8
+
+const (
+	AUTO_REQUEST_PRIORITY   RequestPriority = iota // Interaction responses (callbacks & interaction webhooks) use HIGH priority, everything else uses NORMAL priority.
+	LOW_REQUEST_PRIORITY                           // Background jobs (mass DMs, bulk synchronization, etc.).
+	NORMAL_REQUEST_PRIORITY                        // Regular actions triggered by users.
+	HIGH_REQUEST_PRIORITY                          // Interaction callbacks, follow-ups and other time critical requests.
+)
+
+// Number of waiters queued at each priority.
+type QueueDepth struct {
+	Low    int
+	Normal int
+	High   int
+}
+
+func (d QueueDepth) Total() int {
+	return d.Low + d.Normal + d.High
+}
+
+// Snapshot of single rate limit bucket.
+type BucketQueueStats struct {
+	ResetAt   time.Time
+	ID        string
+	Remaining int
+	Limit     int
+	Queued    QueueDepth
+}
+
+// Snapshot of rate limiter queues. Use RateLimiter.QueueStats or Rest.QueueStats to get it.
+type RateLimiterStats struct {
+	Buckets []BucketQueueStats // Only buckets with at least 1 queued request are listed.
+	Global  QueueDepth         // Requests that already got bucket slot but wait for global limit.
+}
+
 // Represents a Discord rate limit bucket.
 type Bucket struct {
 	ResetAt   time.Time
 	ID        string
 	Remaining int
 	Limit     int
+	queue     fairQueue
 	mu        sync.Mutex
 }
 
+// Caller must hold the lock.
+func (b *Bucket) tryTake(now time.Time) (bool, time.Time) {
+	if b.Remaining <= 0 && !now.Before(b.ResetAt) {
+		b.Remaining = max(b.Limit, 1)
+		b.ResetAt = now.Add(time.Second) // Placeholder until Discord reports real reset time.
+	}
+
+	if b.Remaining > 0 {
+		b.Remaining--
+		return true, time.Time{}
+	}
+	return false, b.ResetAt
+}
+
+// Caller must hold the lock.
+func (b *Bucket) giveBack() {
+	b.Remaining++
+}
+
 type RateLimiterOptions struct {
 	TraceLogger    *log.Logger
 	SweepInterval  time.Duration // By default: 30 minutes
 	SweepThreshold int           // By default: 2500 buckets
+	GlobalLimit    int           // Max number of requests per second, shared by all routes. Interaction callbacks & interaction webhooks are exempt. By default: 50 (same as Discord).
+	ApplicationID  Snowflake     // Used to recognize interaction webhooks (follow-ups & original response edits). Rest fills it from bot token.
 	Trace          bool
 }
 
@@ -33,7 +95,9 @@ type RateLimiter struct {
 	buckets        map[string]*Bucket // Bucket ID -> Bucket
 	routeMapping   map[string]string  // Route (Method:Path) -> Bucket ID
 	traceLogger    *log.Logger
+	global         globalGate
 	globalWait     atomic.Int64
+	applicationID  Snowflake
 	sweepInterval  time.Duration
 	sweepThreshold int
 	mu             sync.RWMutex
@@ -51,20 +115,30 @@ func NewRateLimiter(opt RateLimiterOptions) *RateLimiter {
 		sweepThreshold = 2500
 	}
 
+	globalLimit := opt.GlobalLimit
+	if globalLimit <= 0 {
+		globalLimit = 50
+	}
+
 	trace := opt.Trace
 	if !trace && opt.TraceLogger != nil {
 		trace = opt.TraceLogger.Writer() != io.Discard
 	}
 
-	return &RateLimiter{
+	rl := &RateLimiter{
 		buckets:        make(map[string]*Bucket),
 		routeMapping:   make(map[string]string),
 		lastSweep:      time.Now(),
 		sweepInterval:  sweepInterval,
 		sweepThreshold: sweepThreshold,
 		traceLogger:    opt.TraceLogger,
+		applicationID:  opt.ApplicationID,
 		trace:          trace,
 	}
+
+	rl.global.limit = globalLimit
+	rl.global.globalWait = &rl.globalWait
+	return rl
 }
 
 func (rl *RateLimiter) tracef(format string, v ...any) {
@@ -77,75 +151,67 @@ func (rl *RateLimiter) tracef(format string, v ...any) {
 	}
 }
 
+// Waits (with normal priority) until request to provided route can be sent.
+// Returned function must be called with response headers (or nil on failure) once request is done.
 func (rl *RateLimiter) Wait(route string) func(headers http.Header) {
-	globalWaitNano := rl.globalWait.Load()
-	if globalWaitNano != 0 {
-		globalWait := time.Unix(0, globalWaitNano)
-		if time.Now().Before(globalWait) {
-			wait := time.Until(globalWait)
-			rl.tracef("Global rate limit hit! Waiting %s...", wait.Round(time.Millisecond))
-			time.Sleep(wait)
+	release, _ := rl.WaitWithPriority(context.Background(), route, NORMAL_REQUEST_PRIORITY)
+	return release
+}
+
+// Works like Wait but gives request bigger (or smaller) share of slots than queued requests with other priorities.
+// Besides route's bucket, every request (except interaction responses) counts against global limit (see RateLimiterOptions.GlobalLimit).
+// It returns error only if context gets cancelled before request receives its slot.
+func (rl *RateLimiter) WaitWithPriority(ctx context.Context, route string, priority RequestPriority) (func(headers http.Header), error) {
+	_, path, _ := strings.Cut(route, ":")
+	interaction := isInteractionPath(path, rl.applicationID)
+
+	if priority == AUTO_REQUEST_PRIORITY {
+		priority = NORMAL_REQUEST_PRIORITY
+		if interaction {
+			priority = HIGH_REQUEST_PRIORITY
 		}
 	}
 
-	rl.mu.Lock()
-	if time.Since(rl.lastSweep) > rl.sweepInterval {
-		rl.lastSweep = time.Now()
-		if len(rl.routeMapping) > rl.sweepThreshold {
-			clear(rl.routeMapping)
-		}
+	bucket := rl.bucketOf(route)
 
-		now := time.Now()
-		for id, b := range rl.buckets {
-			if b.mu.TryLock() {
-				if now.After(b.ResetAt) {
-					delete(rl.buckets, id)
-				}
-				b.mu.Unlock()
-			}
-		}
+	bucket.mu.Lock()
+	queued, err := bucket.queue.wait(ctx, &bucket.mu, priority, bucket.tryTake, bucket.giveBack)
+	if err != nil {
+		return nil, err
 	}
 
-	bucketID, ok := rl.routeMapping[route]
-	if !ok {
-		bucketID = route
-		rl.routeMapping[route] = bucketID
+	if queued {
+		rl.tracef("Rate limit hit on route \"%s\" (bucket ID: %s), request waited in queue.", route, bucket.ID)
 	}
 
-	bucket, ok := rl.buckets[bucketID]
-	if !ok {
-		bucket = &Bucket{
-			ID:        bucketID,
-			Limit:     1,
-			Remaining: 1,
+	// Interaction responses are not bound to the global rate limit.
+	if !interaction {
+		rl.global.mu.Lock()
+		queued, err = rl.global.queue.wait(ctx, &rl.global.mu, priority, rl.global.tryTake, rl.global.giveBack)
+		if err != nil {
+			bucket.mu.Lock()
+			bucket.giveBack()
+			bucket.queue.dispatch(&bucket.mu, bucket.tryTake)
+			bucket.mu.Unlock()
+			return nil, err
 		}
-		rl.buckets[bucketID] = bucket
-	}
-	rl.mu.Unlock()
-
-	bucket.mu.Lock()
 
-	if bucket.Remaining <= 0 {
-		if time.Now().Before(bucket.ResetAt) {
-			waitDuration := time.Until(bucket.ResetAt)
-			bucket.mu.Unlock()
-			rl.tracef("Rate limit hit on route \"%s\" (bucket ID: %s)! Waiting %s...", route, bucket.ID, waitDuration.Round(time.Millisecond))
-			time.Sleep(waitDuration)
-			return rl.Wait(route)
+		if queued {
+			rl.tracef("Global rate limit hit on route \"%s\", request waited in queue.", route)
 		}
 	}
 
-	bucket.Remaining--
-	bucket.mu.Unlock()
-
 	return func(headers http.Header) {
+		bucket.mu.Lock()
+		defer bucket.mu.Unlock()
+
+		// Without rate limit headers there's nothing to learn from, so slot is passed to the next request.
 		if headers == nil {
+			bucket.giveBack()
+			bucket.queue.dispatch(&bucket.mu, bucket.tryTake)
 			return
 		}
 
-		bucket.mu.Lock()
-		defer bucket.mu.Unlock()
-
 		retryAfterStr := headers.Get("Retry-After")
 		if headers.Get("X-RateLimit-Global") == "true" {
 			if retryAfterStr != "" {
@@ -154,11 +220,17 @@ func (rl *RateLimiter) Wait(route string) func(headers http.Header) {
 
 				rl.tracef("Received global rate limit! Retry after: %f", retryAfter)
 			}
+
+			// Request never reached route's bucket, so its slot goes back to queued requests (they'll wait for global limit anyway).
+			bucket.giveBack()
+			bucket.queue.dispatch(&bucket.mu, bucket.tryTake)
 			return
 		}
 
 		bucketHeader := headers.Get("X-RateLimit-Bucket")
 		if bucketHeader == "" {
+			bucket.giveBack()
+			bucket.queue.dispatch(&bucket.mu, bucket.tryTake)
 			return
 		}
 
@@ -190,17 +262,268 @@ func (rl *RateLimiter) Wait(route string) func(headers http.Header) {
 			resetAfter, _ := strconv.ParseFloat(resetAfterStr, 64)
 			bucket.ResetAt = time.Now().Add(time.Duration(resetAfter*float64(time.Second)) + 100*time.Millisecond)
 		}
+
+		bucket.queue.dispatch(&bucket.mu, bucket.tryTake)
+	}, nil
+}
+
+// Finds (or creates) bucket for provided route. It also sweeps old buckets from time to time.
+func (rl *RateLimiter) bucketOf(route string) *Bucket {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if time.Since(rl.lastSweep) > rl.sweepInterval {
+		rl.lastSweep = time.Now()
+		if len(rl.routeMapping) > rl.sweepThreshold {
+			clear(rl.routeMapping)
+		}
+
+		now := time.Now()
+		for id, b := range rl.buckets {
+			if b.mu.TryLock() {
+				if now.After(b.ResetAt) && b.queue.len() == 0 {
+					delete(rl.buckets, id)
+				}
+				b.mu.Unlock()
+			}
+		}
+	}
+
+	bucketID, ok := rl.routeMapping[route]
+	if !ok {
+		bucketID = route
+		rl.routeMapping[route] = bucketID
+	}
+
+	bucket, ok := rl.buckets[bucketID]
+	if !ok {
+		bucket = &Bucket{
+			ID:        bucketID,
+			Limit:     1,
+			Remaining: 1,
+		}
+		rl.buckets[bucketID] = bucket
+	}
+
+	return bucket
+}
+
+// Returns current depth of request queues.
+func (rl *RateLimiter) QueueStats() RateLimiterStats {
+	rl.mu.RLock()
+	buckets := make([]*Bucket, 0, len(rl.buckets))
+	for _, b := range rl.buckets {
+		buckets = append(buckets, b)
+	}
+	rl.mu.RUnlock()
+
+	stats := RateLimiterStats{}
+	for _, b := range buckets {
+		b.mu.Lock()
+		if b.queue.len() != 0 {
+			stats.Buckets = append(stats.Buckets, BucketQueueStats{
+				ResetAt:   b.ResetAt,
+				ID:        b.ID,
+				Remaining: b.Remaining,
+				Limit:     b.Limit,
+				Queued:    b.queue.depth(),
+			})
+		}
+		b.mu.Unlock()
+	}
+
+	rl.global.mu.Lock()
+	stats.Global = rl.global.queue.depth()
+	rl.global.mu.Unlock()
+
+	return stats
+}
+
+// Fixed window limiter for the global (per bot) rate limit.
+type globalGate struct {
+	windowStart time.Time
+	globalWait  *atomic.Int64 // UnixNano, set when Discord reports global rate limit.
+	queue       fairQueue
+	used        int
+	limit       int
+	mu          sync.Mutex
+}
+
+// Caller must hold the lock.
+func (g *globalGate) tryTake(now time.Time) (bool, time.Time) {
+	if waitNano := g.globalWait.Load(); waitNano != 0 {
+		if until := time.Unix(0, waitNano); now.Before(until) {
+			return false, until
+		}
+	}
+
+	if now.Sub(g.windowStart) >= time.Second {
+		g.windowStart = now
+		g.used = 0
+	}
+
+	if g.used < g.limit {
+		g.used++
+		return true, time.Time{}
+	}
+
+	return false, g.windowStart.Add(time.Second)
+}
+
+// Caller must hold the lock.
+func (g *globalGate) giveBack() {
+	if g.used > 0 {
+		g.used--
+	}
+}
+
+type queuedWaiter struct {
+	ready chan struct{}
+}
+
+// Order in which lanes take turns when all of them have waiters - out of every 7 slots, high priority gets 4, normal 2 and low 1.
+var fairQueueSchedule = [...]int{2, 1, 2, 0, 2, 1, 2}
+
+// Queue of requests waiting for rate limit slot, split into lanes by priority.
+// All methods must be called with lock of the owner (bucket or global gate) held.
+type fairQueue struct {
+	timer *time.Timer
+	lanes [3][]*queuedWaiter // Low, normal & high priority.
+	turn  int                // Position in fairQueueSchedule.
+}
+
+func (q *fairQueue) len() int {
+	return len(q.lanes[0]) + len(q.lanes[1]) + len(q.lanes[2])
+}
+
+func (q *fairQueue) depth() QueueDepth {
+	return QueueDepth{Low: len(q.lanes[0]), Normal: len(q.lanes[1]), High: len(q.lanes[2])}
+}
+
+// Lock must be held on entry - it's always released before function returns.
+// Reports whether request had to wait in queue.
+func (q *fairQueue) wait(
+	ctx context.Context,
+	mu *sync.Mutex,
+	priority RequestPriority,
+	tryTake func(now time.Time) (bool, time.Time),
+	giveBack func(),
+) (bool, error) {
+	if q.len() == 0 {
+		if ok, _ := tryTake(time.Now()); ok {
+			mu.Unlock()
+			return false, nil
+		}
+	}
+
+	lane := min(max(int(priority), 1), 3) - 1
+	w := &queuedWaiter{ready: make(chan struct{})}
+	q.lanes[lane] = append(q.lanes[lane], w)
+	q.dispatch(mu, tryTake)
+	mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return true, nil
+	case <-ctx.Done():
+		mu.Lock()
+		defer mu.Unlock()
+
+		for i, queued := range q.lanes[lane] {
+			if queued == w {
+				q.lanes[lane] = append(q.lanes[lane][:i], q.lanes[lane][i+1:]...)
+				return true, ctx.Err()
+			}
+		}
+
+		// Slot got granted at the same time - pass it to the next waiter.
+		giveBack()
+		q.dispatch(mu, tryTake)
+		return true, ctx.Err()
 	}
 }
 
+// Grants slots to queued waiters (in weighted round-robin order) for as long as tryTake allows it.
+// When slots run out, timer is armed to try again once the limit resets.
+func (q *fairQueue) dispatch(mu *sync.Mutex, tryTake func(now time.Time) (bool, time.Time)) {
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	for q.len() != 0 {
+		ok, retryAt := tryTake(time.Now())
+		if !ok {
+			q.timer = time.AfterFunc(time.Until(retryAt), func() {
+				mu.Lock()
+				defer mu.Unlock()
+				q.timer = nil
+				q.dispatch(mu, tryTake)
+			})
+			return
+		}
+
+		close(q.next().ready)
+	}
+}
+
+// Removes and returns waiter whose lane has the next turn. Queue must not be empty.
+func (q *fairQueue) next() *queuedWaiter {
+	for i := range len(fairQueueSchedule) {
+		lane := fairQueueSchedule[(q.turn+i)%len(fairQueueSchedule)]
+		if len(q.lanes[lane]) == 0 {
+			continue
+		}
+
+		q.turn = (q.turn + i + 1) % len(fairQueueSchedule)
+		w := q.lanes[lane][0]
+		q.lanes[lane][0] = nil
+		q.lanes[lane] = q.lanes[lane][1:]
+		return w
+	}
+	panic("fairQueue.next called on empty queue")
+}
+
+// Reports whether path (with or without "/api/vN" prefix) leads to interaction response endpoint:
+// callback (/interactions/...) or interaction webhook (/webhooks/{applicationID}/...) used for follow-ups & original response edits.
+func isInteractionPath(path string, applicationID Snowflake) bool {
+	if strings.HasPrefix(path, "/api/v") {
+		if idx := strings.Index(path[6:], "/"); idx != -1 {
+			path = path[6+idx:]
+		}
+	}
+
+	if strings.HasPrefix(path, "/interactions/") {
+		return true
+	}
+	return applicationID != 0 && strings.HasPrefix(path, "/webhooks/"+applicationID.String()+"/")
+}
+
+// Returns ID of the bucket that route (in "METHOD:/normalised/path" format) is currently mapped to.
+// Until Discord reports real bucket for given route, the route itself is used as bucket ID.
+func (rl *RateLimiter) BucketID(route string) string {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	if bucketID, ok := rl.routeMapping[route]; ok {
+		return bucketID
+	}
+	return route
+}
+
 type rateLimitTransport struct {
 	limiter        *RateLimiter
 	innerTransport http.RoundTripper
 }
 
 func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	route := fmt.Sprintf("%s:%s", req.Method, extractRoute(req.URL.Path))
-	unlock := t.limiter.Wait(route)
+	route := rateLimitRoute(req.Method, req.URL.Path)
+
+	priority, _ := req.Context().Value(requestPriorityKey{}).(RequestPriority)
+	unlock, err := t.limiter.WaitWithPriority(req.Context(), route, priority)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := t.innerTransport.RoundTrip(req)
 	if err != nil {
@@ -212,6 +535,10 @@ func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error
 	return resp, nil
 }
 
+func rateLimitRoute(method string, path string) string {
+	return fmt.Sprintf("%s:%s", method, extractRoute(path))
+}
+
 func extractRoute(path string) string {
 	orig := path
 	if strings.HasPrefix(path, "/api/v") {
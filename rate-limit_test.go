@@ -0,0 +1,176 @@
+package tempest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFairQueueOrder(t *testing.T) {
+	var (
+		q       fairQueue
+		mu      sync.Mutex
+		waiters = map[*queuedWaiter]RequestPriority{}
+	)
+
+	for _, priority := range []RequestPriority{LOW_REQUEST_PRIORITY, NORMAL_REQUEST_PRIORITY, HIGH_REQUEST_PRIORITY} {
+		for range 8 {
+			w := &queuedWaiter{ready: make(chan struct{})}
+			q.lanes[priority-1] = append(q.lanes[priority-1], w)
+			waiters[w] = priority
+		}
+	}
+
+	granted := make([]RequestPriority, 0, 7)
+	for range 7 {
+		slots := 1
+		mu.Lock()
+		q.dispatch(&mu, func(time.Time) (bool, time.Time) {
+			if slots == 0 {
+				return false, time.Now().Add(time.Hour)
+			}
+			slots--
+			return true, time.Time{}
+		})
+		mu.Unlock()
+
+		for w, priority := range waiters {
+			select {
+			case <-w.ready:
+				granted = append(granted, priority)
+				delete(waiters, w)
+			default:
+			}
+		}
+	}
+	q.timer.Stop()
+
+	H, N, L := HIGH_REQUEST_PRIORITY, NORMAL_REQUEST_PRIORITY, LOW_REQUEST_PRIORITY
+	expected := []RequestPriority{H, N, H, L, H, N, H}
+	if len(granted) != len(expected) {
+		t.Fatalf("expected %d grants, got %v", len(expected), granted)
+	}
+
+	for i := range expected {
+		if granted[i] != expected[i] {
+			t.Fatalf("expected grant order %v, got %v", expected, granted)
+		}
+	}
+
+	if depth := q.depth(); depth != (QueueDepth{Low: 7, Normal: 6, High: 4}) {
+		t.Errorf("unexpected queue depth after 7 grants: %+v", depth)
+	}
+}
+
+func TestRateLimiterCancelWhileQueued(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterOptions{})
+	route := "GET:/channels/1/messages"
+
+	release, err := rl.WaitWithPriority(context.Background(), route, NORMAL_REQUEST_PRIORITY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release(http.Header{
+		"X-Ratelimit-Bucket":      {"abc"},
+		"X-Ratelimit-Limit":       {"1"},
+		"X-Ratelimit-Remaining":   {"0"},
+		"X-Ratelimit-Reset-After": {"60"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := rl.WaitWithPriority(ctx, route, HIGH_REQUEST_PRIORITY); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error while queued, got %v", err)
+	}
+
+	if stats := rl.QueueStats(); len(stats.Buckets) != 0 {
+		t.Errorf("cancelled request should leave the queue, got %+v", stats.Buckets)
+	}
+}
+
+func TestRateLimiterGlobalLimitGivesSlotBack(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterOptions{})
+	route := "GET:/channels/1/messages"
+
+	release, err := rl.WaitWithPriority(context.Background(), route, NORMAL_REQUEST_PRIORITY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release(http.Header{
+		"X-Ratelimit-Bucket":      {"abc"},
+		"X-Ratelimit-Limit":       {"1"},
+		"X-Ratelimit-Remaining":   {"1"},
+		"X-Ratelimit-Reset-After": {"60"},
+	})
+
+	release, err = rl.WaitWithPriority(context.Background(), route, NORMAL_REQUEST_PRIORITY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release(http.Header{"X-Ratelimit-Global": {"true"}, "Retry-After": {"0"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := rl.WaitWithPriority(ctx, route, NORMAL_REQUEST_PRIORITY); err != nil {
+		t.Fatalf("expected route slot to be given back after global rate limit, got %v", err)
+	}
+}
+
+func TestGlobalGateRefill(t *testing.T) {
+	var wait atomic.Int64
+	gate := globalGate{limit: 2, globalWait: &wait}
+	now := time.Now()
+
+	for i := range 2 {
+		if ok, _ := gate.tryTake(now); !ok {
+			t.Fatalf("request #%d should fit in global limit", i+1)
+		}
+	}
+
+	if ok, retryAt := gate.tryTake(now.Add(500 * time.Millisecond)); ok || !retryAt.Equal(now.Add(time.Second)) {
+		t.Fatalf("expected exhausted window until %v, got ok = %t, retryAt = %v", now.Add(time.Second), ok, retryAt)
+	}
+
+	gate.giveBack()
+	if ok, _ := gate.tryTake(now.Add(500 * time.Millisecond)); !ok {
+		t.Error("slot given back should be reusable within the same window")
+	}
+
+	if ok, _ := gate.tryTake(now.Add(time.Second)); !ok {
+		t.Error("expected window to refill after a second")
+	}
+
+	wait.Store(now.Add(5 * time.Second).UnixNano())
+	if ok, retryAt := gate.tryTake(now.Add(2 * time.Second)); ok || !retryAt.Equal(time.Unix(0, wait.Load())) {
+		t.Errorf("expected gate to stay closed while Discord reports global rate limit, got ok = %t, retryAt = %v", ok, retryAt)
+	}
+}
+
+func TestIsInteractionPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected bool
+	}{
+		{"/api/v10/interactions/1/token/callback", true},
+		{"/interactions/1/token/callback", true},
+		{"/webhooks/42/token/messages/@original", true},
+		{"/api/v10/webhooks/42/token", true},
+		{"/webhooks/7/token", false}, // Regular (non-interaction) webhook.
+		{"/channels/1/messages", false},
+	}
+
+	for _, tc := range cases {
+		if got := isInteractionPath(tc.path, 42); got != tc.expected {
+			t.Errorf("isInteractionPath(%q) = %t, expected %t", tc.path, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package tempest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// Represents file you can attach to message on Discord.
+//
+// Files are streamed straight from their source when every attached file is either io.ReadSeeker (like *os.File or *bytes.Reader)
+// or provides Open factory - that way request can be safely retried without holding whole files in memory.
+// Otherwise, whole multipart body gets buffered in memory first.
+type File struct {
+	Reader      io.Reader                     // Source of file content. Ignored when Open is provided.
+	Open        func() (io.ReadCloser, error) // Optional factory that returns fresh reader for each request attempt. Returned reader is closed by client.
+	Name        string                        // File's display name.
+	Description string                        // Optional alt text (max 1024 characters).
+	ContentType string                        // Optional MIME type. By default it's guessed from file extension or sniffed from file content.
+	Spoiler     bool                          // Whether file should be hidden behind spoiler.
+}
+
+// Returns max size (in bytes) of single file that can be uploaded in guild with provided premium tier.
+// Use Interaction.AttachmentSizeLimit instead when responding to interactions - it also respects user's Nitro.
+//
+// https://support.discord.com/hc/en-us/articles/360028038352-Server-Boosting-FAQ
+func FileUploadLimit(tier PremiumTier) int64 {
+	switch tier {
+	case BOOST_2_PREMIUM_TIER:
+		return 50 * 1024 * 1024
+	case BOOST_3_PREMIUM_TIER:
+		return 100 * 1024 * 1024
+	default:
+		return MAX_FILE_UPLOAD_SIZE
+	}
+}
+
+// Sends multipart request with attached files. Each file may have up to RestOptions.MaxFileUploadSize bytes.
+func (rest *Rest) RequestWithFiles(method string, route string, jsonPayload any, files []File) ([]byte, error) {
+	return rest.RequestWithFilesLimit(method, route, jsonPayload, files, 0)
+}
+
+// Works like RequestWithFiles but uses custom max size (in bytes) of each file.
+// Use it with FileUploadLimit or Interaction.AttachmentSizeLimit to upload files larger than default limit. Set limit to 0 to use default value.
+func (rest *Rest) RequestWithFilesLimit(method string, route string, jsonPayload any, files []File, limit int64) ([]byte, error) {
+	if len(files) == 0 {
+		return rest.Request(method, route, jsonPayload)
+	}
+
+	if limit <= 0 {
+		limit = rest.maxFileUploadSize
+	}
+
+	payloadJSON, err := encodeFilesPayload(jsonPayload, files)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, streamable, err := prepareUploads(files, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	contentType := "multipart/form-data; boundary=" + boundary
+
+	if !streamable {
+		// At least one file can't be rewound, so we pre-buffer the multipart request into memory.
+		// This ensures that the request body can be reliably sent multiple times in case of retries.
+		var requestBody bytes.Buffer
+		if err := writeMultipart(&requestBody, boundary, payloadJSON, uploads, limit); err != nil {
+			return nil, err
+		}
+
+		return rest.DirectRequest(method, route, bytes.NewReader(requestBody.Bytes()), contentType, "")
+	}
+
+	contentLength, err := multipartLength(boundary, payloadJSON, uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	return rest.do(method, route, func() (io.Reader, func() error, error) {
+		pr, pw := io.Pipe()
+		done := make(chan error, 1)
+
+		go func() {
+			err := writeMultipart(pw, boundary, payloadJSON, uploads, limit)
+			pw.CloseWithError(err) //nolint:errcheck
+			done <- err
+		}()
+
+		// Pipe reader gets closed by http transport but we close it again
+		// in case request never got sent, so writer goroutine can't hang.
+		return pr, func() error {
+			pr.Close() //nolint:errcheck
+			if err := <-done; err != nil && !errors.Is(err, io.ErrClosedPipe) {
+				return err
+			}
+			return nil
+		}, nil
+	}, contentLength, contentType, RequestOptions{})
+}
+
+type fileUpload struct {
+	file        File
+	name        string
+	contentType string // Empty means content type will be sniffed while writing.
+	size        int64  // -1 when unknown.
+}
+
+// Returns name that file is uploaded under - Discord marks attachment as spoiler by its "SPOILER_" prefix.
+func uploadFileName(file File) string {
+	if file.Spoiler && !strings.HasPrefix(file.Name, "SPOILER_") {
+		return "SPOILER_" + file.Name
+	}
+	return file.Name
+}
+
+// Resolves names, content types & sizes of files. Reports whether all files can be rewound for retries.
+func prepareUploads(files []File, limit int64) ([]fileUpload, bool, error) {
+	uploads := make([]fileUpload, len(files))
+	streamable := true
+
+	for i, file := range files {
+		upload := fileUpload{
+			file:        file,
+			name:        uploadFileName(file),
+			contentType: file.ContentType,
+			size:        -1,
+		}
+
+		if upload.contentType == "" {
+			upload.contentType = mime.TypeByExtension(filepath.Ext(file.Name))
+		}
+
+		if file.Open == nil {
+			if file.Reader == nil {
+				return nil, false, fmt.Errorf("file [%s] has neither Reader nor Open provided", file.Name)
+			}
+
+			seeker, ok := file.Reader.(io.ReadSeeker)
+			if !ok {
+				streamable = false
+			} else {
+				size, err := seeker.Seek(0, io.SeekEnd)
+				if err != nil {
+					return nil, false, fmt.Errorf("failed to seek file [%s]: %w", file.Name, err)
+				}
+
+				if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+					return nil, false, fmt.Errorf("failed to seek file [%s]: %w", file.Name, err)
+				}
+
+				if size > limit {
+					return nil, false, fmt.Errorf("file [%s] exceeds maximum upload size of %d bytes", file.Name, limit)
+				}
+				upload.size = size
+
+				if upload.contentType == "" {
+					contentType, err := sniffContentType(seeker)
+					if err != nil {
+						return nil, false, fmt.Errorf("failed to read file [%s]: %w", file.Name, err)
+					}
+					upload.contentType = contentType
+				}
+			}
+		}
+
+		uploads[i] = upload
+	}
+
+	return uploads, streamable, nil
+}
+
+// Detects content type from the first 512 bytes and rewinds reader back to the start.
+func sniffContentType(seeker io.ReadSeeker) (string, error) {
+	var head [512]byte
+	n, err := io.ReadFull(seeker, head[:])
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
+
+// Encodes payload_json part. When any file has description, it also fills "attachments" array
+// (unless payload already provides own, non-empty list) as that's the only place Discord reads it from.
+func encodeFilesPayload(jsonPayload any, files []File) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(jsonPayload); err != nil {
+		return nil, fmt.Errorf("failed to encode payload_json: %w", err)
+	}
+
+	hasDescription := false
+	for _, file := range files {
+		if file.Description != "" {
+			hasDescription = true
+			break
+		}
+	}
+
+	if !hasDescription {
+		return buf.Bytes(), nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil || fields == nil {
+		return buf.Bytes(), nil // Not a JSON object, leave it as it is.
+	}
+
+	if raw, ok := fields["attachments"]; ok {
+		var existing []json.RawMessage
+		if err := json.Unmarshal(raw, &existing); err != nil || len(existing) != 0 {
+			return buf.Bytes(), nil
+		}
+	}
+
+	type partialAttachment struct {
+		Filename    string `json:"filename"`
+		Description string `json:"description,omitempty"`
+		ID          int    `json:"id"`
+	}
+
+	attachments := make([]partialAttachment, len(files))
+	for i, file := range files {
+		attachments[i] = partialAttachment{ID: i, Filename: uploadFileName(file), Description: file.Description}
+	}
+
+	raw, err := json.Marshal(attachments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attachments: %w", err)
+	}
+	fields["attachments"] = raw
+
+	buf.Reset()
+	if err := encoder.Encode(fields); err != nil {
+		return nil, fmt.Errorf("failed to encode payload_json: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func filePartHeader(i int, upload fileUpload, contentType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Disposition": []string{fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, escapeQuotes(upload.name))},
+		"Content-Type":        []string{contentType},
+	}
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// Writes the JSON payload and files as multipart body.
+func writeMultipart(dst io.Writer, boundary string, payloadJSON []byte, uploads []fileUpload, limit int64) error {
+	writer := multipart.NewWriter(dst)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return fmt.Errorf("failed to set multipart boundary: %w", err)
+	}
+
+	jsonPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": []string{CONTENT_MULTIPART_JSON_DESCRIPTION},
+		"Content-Type":        []string{CONTENT_TYPE_JSON},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create payload_json part: %w", err)
+	}
+
+	if _, err := jsonPart.Write(payloadJSON); err != nil {
+		return fmt.Errorf("failed to write payload_json: %w", err)
+	}
+
+	for i, upload := range uploads {
+		if err := writeFilePart(writer, i, upload, limit); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	return nil
+}
+
+func writeFilePart(writer *multipart.Writer, i int, upload fileUpload, limit int64) error {
+	var src io.Reader
+	if upload.file.Open != nil {
+		rc, err := upload.file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open file [%s]: %w", upload.file.Name, err)
+		}
+		defer rc.Close() //nolint:errcheck
+		src = rc
+	} else {
+		src = upload.file.Reader
+		if seeker, ok := src.(io.ReadSeeker); ok {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("failed to seek file [%s]: %w", upload.file.Name, err)
+			}
+		}
+	}
+
+	contentType := upload.contentType
+	if contentType == "" {
+		br := bufio.NewReaderSize(src, 512)
+		head, err := br.Peek(512)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+			return fmt.Errorf("failed to read file [%s]: %w", upload.file.Name, err)
+		}
+
+		contentType = http.DetectContentType(head)
+		src = br
+	}
+
+	filePart, err := writer.CreatePart(filePartHeader(i, upload, contentType))
+	if err != nil {
+		return fmt.Errorf("failed to create file part [%d]: %w", i, err)
+	}
+
+	n, err := io.Copy(filePart, io.LimitReader(src, limit+1))
+	if err != nil {
+		return fmt.Errorf("failed to stream file [%s]: %w", upload.file.Name, err)
+	}
+
+	if n > limit {
+		return fmt.Errorf("file [%s] exceeds maximum upload size of %d bytes", upload.file.Name, limit)
+	}
+
+	if upload.size >= 0 && n != upload.size {
+		return fmt.Errorf("file [%s] changed size while uploading (expected %d bytes, got %d)", upload.file.Name, upload.size, n)
+	}
+
+	return nil
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// Calculates exact length of multipart body without reading any file. Returns -1 when size of any file is unknown.
+func multipartLength(boundary string, payloadJSON []byte, uploads []fileUpload) (int64, error) {
+	var total countingWriter
+	writer := multipart.NewWriter(&total)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return 0, fmt.Errorf("failed to set multipart boundary: %w", err)
+	}
+
+	jsonPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": []string{CONTENT_MULTIPART_JSON_DESCRIPTION},
+		"Content-Type":        []string{CONTENT_TYPE_JSON},
+	})
+	if err != nil {
+		return 0, err
+	}
+	jsonPart.Write(payloadJSON) //nolint:errcheck
+
+	for i, upload := range uploads {
+		if upload.size < 0 || upload.contentType == "" {
+			return -1, nil
+		}
+
+		if _, err := writer.CreatePart(filePartHeader(i, upload, upload.contentType)); err != nil {
+			return 0, err
+		}
+		total += countingWriter(upload.size)
+	}
+
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+
+	return int64(total), nil
+}
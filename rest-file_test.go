@@ -0,0 +1,105 @@
+package tempest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMultipartLength(t *testing.T) {
+	files := []File{
+		{Name: "cat.png", Reader: bytes.NewReader([]byte("\x89PNG\r\n\x1a\nfake image")), Spoiler: true},
+		{Name: `quote".txt`, Reader: strings.NewReader("hello"), Description: "Greeting"},
+		{Name: "data", Open: func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("{}")), nil }, ContentType: "application/json"},
+	}
+
+	payloadJSON, err := encodeFilesPayload(map[string]any{"content": "files"}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploads, streamable, err := prepareUploads(files[:2], MAX_FILE_UPLOAD_SIZE)
+	if err != nil || !streamable {
+		t.Fatalf("expected seekable files to be streamable (err = %v)", err)
+	}
+
+	boundary := "test-boundary"
+	expected, err := multipartLength(boundary, payloadJSON, uploads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body bytes.Buffer
+	if err := writeMultipart(&body, boundary, payloadJSON, uploads, MAX_FILE_UPLOAD_SIZE); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected != int64(body.Len()) {
+		t.Errorf("multipartLength = %d, but writeMultipart wrote %d bytes", expected, body.Len())
+	}
+
+	// Files opened with factory have unknown size, so request falls back to chunked encoding.
+	uploads, _, err = prepareUploads(files, MAX_FILE_UPLOAD_SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if length, err := multipartLength(boundary, payloadJSON, uploads); err != nil || length != -1 {
+		t.Errorf("expected unknown length (-1), got %d (err = %v)", length, err)
+	}
+}
+
+func TestEncodeFilesPayload(t *testing.T) {
+	files := []File{{Name: "cat.png", Spoiler: true, Description: "A cat"}, {Name: "dog.png"}}
+
+	raw, err := encodeFilesPayload(map[string]any{"content": "<pets>"}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		Content     string `json:"content"`
+		Attachments []struct {
+			ID          int    `json:"id"`
+			Filename    string `json:"filename"`
+			Description string `json:"description"`
+		} `json:"attachments"`
+	}
+
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Content != "<pets>" || !bytes.Contains(raw, []byte("<pets>")) {
+		t.Errorf("expected content to be kept without HTML escaping, got %s", raw)
+	}
+
+	if len(payload.Attachments) != 2 {
+		t.Fatalf("expected 2 injected attachments, got %s", raw)
+	}
+
+	first, second := payload.Attachments[0], payload.Attachments[1]
+	if first.ID != 0 || first.Filename != "SPOILER_cat.png" || first.Description != "A cat" || second.ID != 1 || second.Filename != "dog.png" {
+		t.Errorf("unexpected attachments: %+v", payload.Attachments)
+	}
+
+	raw, err = encodeFilesPayload(map[string]any{"attachments": []map[string]any{{"id": 5}}}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(raw, []byte(`"id":5`)) || bytes.Contains(raw, []byte("cat.png")) {
+		t.Errorf("expected payload's own attachments to be kept, got %s", raw)
+	}
+
+	raw, err = encodeFilesPayload(map[string]any{"content": "x"}, []File{{Name: "a.txt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(raw, []byte("attachments")) {
+		t.Errorf("expected no attachments without descriptions, got %s", raw)
+	}
+}
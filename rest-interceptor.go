@@ -0,0 +1,62 @@
+package tempest
+
+import (
+	"net/http"
+)
+
+// Outgoing request that passes through interceptor chain.
+// Interceptors may freely modify HTTP request (headers, context) or replace it entirely.
+type RestRequest struct {
+	HTTP    *http.Request
+	Method  string // Same as HTTP.Method.
+	Route   string // Normalised route (major parameters are kept, everything else is trimmed), same as used by rate limiter.
+	Bucket  string // Discord's rate limit bucket ID, or normalised route when bucket isn't known yet.
+	Attempt uint8  // Starts at 0 and grows with each retry of the same request.
+}
+
+// Sends request and returns Discord's response. Returned response body is always read & closed by Rest client.
+type RoundTrip func(req *RestRequest) (*http.Response, error)
+
+// Wraps next step of the chain. Interceptor may act before and/or after calling next,
+// or skip calling it (for example to return fake response in tests).
+//
+// Remember that returned response body is read after whole chain returns - if you attach
+// timeout context to request, cancel it only after body is closed, never directly after next returns.
+type Interceptor func(next RoundTrip) RoundTrip
+
+// Appends interceptors to the end of chain. First registered interceptor is the outermost one (runs first).
+// Each request passes through chain right before reaching rate limiter & http transport.
+//
+// It's safe to call Use at any time, but requests that are already in flight keep using old chain.
+func (rest *Rest) Use(interceptors ...Interceptor) {
+	rest.interceptorsMu.Lock()
+	defer rest.interceptorsMu.Unlock()
+
+	rest.interceptors = append(rest.interceptors, interceptors...)
+
+	chain := RoundTrip(func(req *RestRequest) (*http.Response, error) {
+		return rest.HTTPClient.Do(req.HTTP)
+	})
+
+	for i := len(rest.interceptors) - 1; i >= 0; i-- {
+		chain = rest.interceptors[i](chain)
+	}
+
+	rest.chain.Store(&chain)
+}
+
+func (rest *Rest) roundTrip(req *http.Request, attempt uint8) (*http.Response, error) {
+	chain := rest.chain.Load()
+	if chain == nil {
+		return rest.HTTPClient.Do(req)
+	}
+
+	path := rateLimitRoute(req.Method, req.URL.Path)
+	return (*chain)(&RestRequest{
+		HTTP:    req,
+		Method:  req.Method,
+		Route:   extractRoute(req.URL.Path),
+		Bucket:  rest.limiter.BucketID(path),
+		Attempt: attempt,
+	})
+}
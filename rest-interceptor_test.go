@@ -0,0 +1,80 @@
+package tempest
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Fake HTTP transport, placed below rate limiter so tests never reach Discord.
+type stubTransport func(req *http.Request) (*http.Response, error)
+
+func (fn stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func newStubRest(transport stubTransport) *Rest {
+	rest := NewRest(RestOptions{Token: "NDI.stub.token"}) // "NDI" = base64("42")
+	rest.HTTPClient.Transport.(*rateLimitTransport).innerTransport = transport
+	return rest
+}
+
+func stubResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Status: strconv.Itoa(status), Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestRestInterceptors(t *testing.T) {
+	var calls []string
+	failures := 1
+	rest := newStubRest(func(*http.Request) (*http.Response, error) {
+		calls = append(calls, "transport")
+		if failures > 0 {
+			failures--
+			return stubResponse(http.StatusBadGateway, ""), nil
+		}
+		return stubResponse(http.StatusOK, `{}`), nil
+	})
+
+	trace := func(name string) Interceptor {
+		return func(next RoundTrip) RoundTrip {
+			return func(req *RestRequest) (*http.Response, error) {
+				calls = append(calls, name+":"+strconv.Itoa(int(req.Attempt)))
+				return next(req)
+			}
+		}
+	}
+
+	rest.Use(trace("outer"), trace("inner"))
+	if _, err := rest.Request(http.MethodGet, "/channels/1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"outer:0", "inner:0", "transport", "outer:1", "inner:1", "transport"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	calls = nil
+	rest = newStubRest(func(*http.Request) (*http.Response, error) {
+		calls = append(calls, "transport")
+		return stubResponse(http.StatusOK, `{}`), nil
+	})
+
+	rest.Use(func(RoundTrip) RoundTrip {
+		return func(*RestRequest) (*http.Response, error) {
+			return stubResponse(http.StatusOK, `{"id":"7"}`), nil
+		}
+	}, trace("skipped"))
+
+	raw, err := rest.Request(http.MethodGet, "/channels/1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(raw) != `{"id":"7"}` || len(calls) != 0 {
+		t.Errorf("expected interceptor to short-circuit chain, got body %s and calls %v", raw, calls)
+	}
+}
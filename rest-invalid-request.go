@@ -0,0 +1,148 @@
+package tempest
+
+import (
+	"sync"
+	"time"
+)
+
+// Discord temporarily bans (on Cloudflare level) every IP address that produces 10,000 invalid requests within 10 minutes.
+// Invalid request means response with 401, 403 or 429 status (429 responses with "shared" scope are not counted).
+//
+// https://docs.discord.com/developers/topics/rate-limits#invalid-request-limit-aka-cloudflare-bans
+const (
+	DISCORD_INVALID_REQUEST_LIMIT  = 10_000
+	DISCORD_INVALID_REQUEST_WINDOW = 10 * time.Minute
+	invalidRequestSlotCount        = 60
+	minInvalidRequestWindow        = time.Second // Shorter windows would make slots (window / 60) too short to count anything.
+)
+
+// Snapshot of the invalid request (401/403/429) tracker state. Use Rest.InvalidRequestStats to get it.
+type InvalidRequestStats struct {
+	Window    time.Duration // Length of the sliding window.
+	Count     uint32        // Number of invalid requests that happened within current window.
+	SoftLimit uint32        // When reached - client starts shedding non-essential requests.
+	HardLimit uint32        // When reached - client stops sending any requests until window slides below it.
+	Total     uint64        // Total number of invalid requests since Rest client creation.
+	Shed      uint64        // Total number of requests rejected by client because of exceeded soft limit.
+	Shedding  bool          // Whether client is currently shedding non-essential requests.
+	Tripped   bool          // Whether hard limit is currently reached.
+}
+
+// Sliding window counter split into fixed number of slots.
+// It trades a tiny bit of precision (1 slot = window / 60) for constant memory usage.
+type invalidRequestTracker struct {
+	onSoftLimit  func(stats InvalidRequestStats)
+	slots        [invalidRequestSlotCount]uint32
+	slotStarts   [invalidRequestSlotCount]int64 // UnixNano
+	window       time.Duration
+	slotDuration time.Duration
+	total        uint64
+	shed         uint64
+	softLimit    uint32
+	hardLimit    uint32
+	mu           sync.Mutex
+	shedding     bool
+}
+
+func newInvalidRequestTracker(window time.Duration, softLimit uint32, hardLimit uint32, onSoftLimit func(stats InvalidRequestStats)) *invalidRequestTracker {
+	if window <= 0 {
+		window = DISCORD_INVALID_REQUEST_WINDOW
+	}
+	window = max(window, minInvalidRequestWindow)
+
+	if hardLimit == 0 {
+		hardLimit = DISCORD_INVALID_REQUEST_LIMIT * 9 / 10
+	}
+
+	if softLimit == 0 || softLimit > hardLimit {
+		softLimit = hardLimit / 2
+	}
+
+	return &invalidRequestTracker{
+		window:       window,
+		slotDuration: window / invalidRequestSlotCount,
+		softLimit:    softLimit,
+		hardLimit:    hardLimit,
+		onSoftLimit:  onSoftLimit,
+	}
+}
+
+// Sums all slots that still fit in the window. Caller must hold the lock.
+func (t *invalidRequestTracker) countLocked(now time.Time) uint32 {
+	oldest := now.Add(-t.window).UnixNano()
+
+	var sum uint32
+	for i := range t.slots {
+		if t.slotStarts[i] > oldest {
+			sum += t.slots[i]
+		}
+	}
+
+	return sum
+}
+
+// Caller must hold the lock.
+func (t *invalidRequestTracker) statsLocked(now time.Time) InvalidRequestStats {
+	count := t.countLocked(now)
+	return InvalidRequestStats{
+		Window:    t.window,
+		Count:     count,
+		SoftLimit: t.softLimit,
+		HardLimit: t.hardLimit,
+		Total:     t.total,
+		Shed:      t.shed,
+		Shedding:  count >= t.softLimit,
+		Tripped:   count >= t.hardLimit,
+	}
+}
+
+// Registers new invalid request. Returns true if hard limit got reached.
+func (t *invalidRequestTracker) record(now time.Time) bool {
+	t.mu.Lock()
+
+	slotStart := now.Truncate(t.slotDuration)
+	idx := (slotStart.UnixNano() / int64(t.slotDuration)) % invalidRequestSlotCount
+	if t.slotStarts[idx] != slotStart.UnixNano() {
+		t.slotStarts[idx] = slotStart.UnixNano()
+		t.slots[idx] = 0
+	}
+
+	t.slots[idx]++
+	t.total++
+
+	stats := t.statsLocked(now)
+	crossedSoftLimit := stats.Shedding && !t.shedding
+	t.shedding = stats.Shedding
+	t.mu.Unlock()
+
+	if crossedSoftLimit && t.onSoftLimit != nil {
+		go t.onSoftLimit(stats)
+	}
+
+	return stats.Tripped
+}
+
+// Returns whether request should be rejected because of exceeded soft limit.
+func (t *invalidRequestTracker) shouldShed(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.shedding {
+		return false
+	}
+
+	// Window slides so re-check whether we're still above soft limit.
+	if t.countLocked(now) < t.softLimit {
+		t.shedding = false
+		return false
+	}
+
+	t.shed++
+	return true
+}
+
+func (t *invalidRequestTracker) stats(now time.Time) InvalidRequestStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statsLocked(now)
+}
@@ -0,0 +1,100 @@
+package tempest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvalidRequestTracker(t *testing.T) {
+	softLimitHits := make(chan InvalidRequestStats, 4)
+	tracker := newInvalidRequestTracker(time.Minute, 3, 5, func(stats InvalidRequestStats) { softLimitHits <- stats })
+	start := time.Unix(1_000_000, 0) // Aligned with 1s slots.
+
+	steps := []struct {
+		name     string
+		at       time.Duration
+		record   bool // Calls record when true, shouldShed otherwise.
+		expected bool // Tripped for record, shed decision for shouldShed.
+	}{
+		{"first invalid request", 0, true, false},
+		{"second invalid request", 0, true, false},
+		{"below soft limit", 500 * time.Millisecond, false, false},
+		{"reaches soft limit", time.Second, true, false},
+		{"sheds above soft limit", time.Second + 500*time.Millisecond, false, true},
+		{"fourth invalid request", 2 * time.Second, true, false},
+		{"reaches hard limit", 2 * time.Second, true, true},
+		{"oldest slot leaves window", 60*time.Second + 500*time.Millisecond, false, true},
+		{"window slides below soft limit", 61 * time.Second, false, false},
+		{"reused slot starts from zero", 120 * time.Second, true, false},
+	}
+
+	for _, step := range steps {
+		now := start.Add(step.at)
+
+		var got bool
+		if step.record {
+			got = tracker.record(now)
+		} else {
+			got = tracker.shouldShed(now)
+		}
+
+		if got != step.expected {
+			t.Fatalf("%s: expected %t, got %t (stats: %+v)", step.name, step.expected, got, tracker.stats(now))
+		}
+	}
+
+	stats := tracker.stats(start.Add(120 * time.Second))
+	if stats.Count != 1 || stats.Total != 6 || stats.Shed != 2 || stats.Shedding {
+		t.Errorf("unexpected final stats: %+v", stats)
+	}
+
+	select {
+	case hit := <-softLimitHits:
+		if hit.Count != 3 {
+			t.Errorf("expected soft limit callback at 3 invalid requests, got %d", hit.Count)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected soft limit callback to run")
+	}
+}
+
+func TestIsEssentialRoute(t *testing.T) {
+	rest := &Rest{applicationID: 42}
+
+	routes := map[string]bool{
+		"/interactions/1/token/callback":        true,
+		"/webhooks/42/token":                    true,
+		"/webhooks/42/token/messages/@original": true,
+		"/webhooks/7/token":                     false,
+		"/channels/1/messages":                  false,
+	}
+
+	for route, expected := range routes {
+		if got := rest.isEssentialRoute(route); got != expected {
+			t.Errorf("isEssentialRoute(%q) = %t, expected %t", route, got, expected)
+		}
+	}
+}
+
+func TestInvalidRequestTrackerWindowBounds(t *testing.T) {
+	cases := []struct {
+		window   time.Duration
+		expected time.Duration
+	}{
+		{0, DISCORD_INVALID_REQUEST_WINDOW},
+		{-time.Minute, DISCORD_INVALID_REQUEST_WINDOW},
+		{time.Nanosecond, minInvalidRequestWindow},
+		{30 * time.Millisecond, minInvalidRequestWindow},
+		{time.Minute, time.Minute},
+	}
+
+	for _, tc := range cases {
+		tracker := newInvalidRequestTracker(tc.window, 0, 0, nil)
+		if tracker.window != tc.expected || tracker.slotDuration <= 0 {
+			t.Errorf("window %v: expected %v with positive slot duration, got %v (slot %v)", tc.window, tc.expected, tracker.window, tracker.slotDuration)
+			continue
+		}
+
+		tracker.record(time.Now()) // Must not divide by zero slot duration.
+	}
+}
@@ -2,18 +2,18 @@ package tempest
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
-	"mime/multipart"
 	"net"
 	"net/http"
-	"net/textproto"
 	"net/url"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -22,14 +22,20 @@ var (
 	errRetryable       = errors.New("a retryable error occurred")
 	errGlobalRateLimit = errors.New("hit global rate limit")
 	errTooManyRetries  = errors.New("internal retry threshold exceeded - your code logic is probably unsafe to use at scale")
+	errInvalidRequests = errors.New("invalid request (401/403/429) hard limit reached - requests are paused to avoid Cloudflare ban")
+	errShedRequest     = errors.New("invalid request (401/403/429) soft limit reached - non-essential request was dropped")
 )
 
-// Represents file you can attach to message on Discord.
-type File struct {
-	Reader io.Reader
-	Name   string // File's display name
+// Extra settings of single request. Zero value works exactly like regular Request() method.
+type RequestOptions struct {
+	Context        context.Context // Optional context that can cancel request (including time spent waiting in rate limit queue).
+	AuditLogReason string          // Optional reason that will be visible in guild's audit log.
+	Authorization  string          // Optional value of Authorization header that replaces bot token (for example "Bearer <token>").
+	Priority       RequestPriority // Decides order of queued requests. By default, it's picked based on route.
 }
 
+type requestPriorityKey struct{}
+
 type rateLimitError struct {
 	Message    string  `json:"message"`
 	RetryAfter float64 `json:"retry_after"`
@@ -37,30 +43,42 @@ type rateLimitError struct {
 }
 
 type Rest struct {
-	limiter        *RateLimiter
-	traceLogger    *log.Logger
-	HTTPClient     http.Client
-	token          string
-	maxWaitTime    time.Duration
-	retryCounter   atomic.Int64
-	retryThreshold int64
-	trippedUntil   atomic.Int64 // UnixNano
-	maxRetries     uint8
-	trace          bool
+	limiter           *RateLimiter
+	invalidRequests   *invalidRequestTracker
+	chain             atomic.Pointer[RoundTrip]
+	interceptors      []Interceptor
+	interceptorsMu    sync.Mutex
+	traceLogger       *log.Logger
+	HTTPClient        http.Client
+	token             string
+	maxWaitTime       time.Duration
+	maxFileUploadSize int64
+	retryCounter      atomic.Int64
+	retryThreshold    int64
+	trippedUntil      atomic.Int64 // UnixNano
+	applicationID     Snowflake
+	maxRetries        uint8
+	trace             bool
 }
 
 type RestOptions struct {
-	TraceLogger        *log.Logger
-	Token              string
-	RateLimiterOptions RateLimiterOptions
-	MaxWaitTime        time.Duration // Max duration it can take for each request.
-	RetryThreshold     uint32        // Max number of concurrent retries allowed before failing all ongoing requests (emergency breaks). By default: 60.
-	MaxRetries         uint8         // By default: 3
-	Trace              bool
+	TraceLogger          *log.Logger
+	Interceptors         []Interceptor                   // Ordered chain of functions that wrap each outgoing request. See Rest.Use for details.
+	OnInvalidRequestHigh func(stats InvalidRequestStats) // Function that runs (in new goroutine) each time number of invalid requests crosses soft limit.
+	Token                string
+	RateLimiterOptions   RateLimiterOptions
+	MaxWaitTime          time.Duration // Max duration it can take for each request.
+	MaxFileUploadSize    int64         // Default max size (in bytes) of each uploaded file. By default: 10MB (limit for guilds without boosts).
+	InvalidRequestWindow time.Duration // Length of sliding window used to count invalid (401/403/429) requests. By default: 10 minutes (same as Discord), at least 1 second.
+	RetryThreshold       uint32        // Max number of concurrent retries allowed before failing all ongoing requests (emergency breaks). By default: 60.
+	InvalidRequestSoft   uint32        // Number of invalid requests within window after which client starts dropping non-essential requests (everything below high priority). By default: half of hard limit.
+	InvalidRequestHard   uint32        // Number of invalid requests within window after which client stops sending any requests (emergency breaks). By default: 9000 (Discord bans at 10,000).
+	MaxRetries           uint8         // By default: 3
+	Trace                bool
 }
 
 func NewRest(opt RestOptions) *Rest {
-	_, err := extractUserIDFromToken(opt.Token)
+	applicationID, err := extractUserIDFromToken(opt.Token)
 	if err != nil {
 		panic("failed to extract bot user ID from bot token: " + err.Error())
 	}
@@ -92,6 +110,11 @@ func NewRest(opt RestOptions) *Rest {
 		maxRetries = 3
 	}
 
+	maxFileUploadSize := opt.MaxFileUploadSize
+	if maxFileUploadSize <= 0 {
+		maxFileUploadSize = MAX_FILE_UPLOAD_SIZE
+	}
+
 	retryThreshold := opt.RetryThreshold
 	if retryThreshold == 0 {
 		retryThreshold = 60
@@ -104,13 +127,17 @@ func NewRest(opt RestOptions) *Rest {
 
 	limiterOptions := opt.RateLimiterOptions
 	limiterOptions.Trace = opt.Trace
+	if limiterOptions.ApplicationID == 0 {
+		limiterOptions.ApplicationID = applicationID
+	}
 	if limiterOptions.TraceLogger == nil {
 		limiterOptions.TraceLogger = traceLogger
 	}
 	limiter := NewRateLimiter(limiterOptions)
 
-	return &Rest{
-		limiter: limiter,
+	rest := &Rest{
+		limiter:         limiter,
+		invalidRequests: newInvalidRequestTracker(opt.InvalidRequestWindow, opt.InvalidRequestSoft, opt.InvalidRequestHard, opt.OnInvalidRequestHigh),
 		HTTPClient: http.Client{
 			Transport: &rateLimitTransport{
 				limiter:        limiter,
@@ -118,13 +145,21 @@ func NewRest(opt RestOptions) *Rest {
 			},
 			Timeout: maxTimeout,
 		},
-		token:          prefixedToken,
-		maxRetries:     maxRetries,
-		maxWaitTime:    maxTimeout,
-		retryThreshold: int64(retryThreshold),
-		traceLogger:    traceLogger,
-		trace:          traceLogger.Writer() != io.Discard,
+		token:             prefixedToken,
+		maxRetries:        maxRetries,
+		maxWaitTime:       maxTimeout,
+		maxFileUploadSize: maxFileUploadSize,
+		retryThreshold:    int64(retryThreshold),
+		traceLogger:       traceLogger,
+		applicationID:     applicationID,
+		trace:             traceLogger.Writer() != io.Discard,
+	}
+
+	if len(opt.Interceptors) != 0 {
+		rest.Use(opt.Interceptors...)
 	}
+
+	return rest
 }
 
 func (rest *Rest) tracef(format string, v ...any) {
@@ -136,6 +171,11 @@ func (rest *Rest) tracef(format string, v ...any) {
 }
 
 func (rest *Rest) Request(method, route string, jsonPayload any) ([]byte, error) {
+	return rest.RequestWithOptions(method, route, jsonPayload, RequestOptions{})
+}
+
+// Works like Request but lets you set priority, audit log reason, context or custom authorization of request.
+func (rest *Rest) RequestWithOptions(method, route string, jsonPayload any, opt RequestOptions) ([]byte, error) {
 	var body io.ReadSeeker
 	if jsonPayload != nil {
 		var buf bytes.Buffer
@@ -147,7 +187,7 @@ func (rest *Rest) Request(method, route string, jsonPayload any) ([]byte, error)
 		body = bytes.NewReader(buf.Bytes())
 	}
 
-	return rest.DirectRequest(method, route, body, CONTENT_TYPE_JSON, "")
+	return rest.do(method, route, seekableBody(body), -1, CONTENT_TYPE_JSON, opt)
 }
 
 // Prepares new json buffered request payload that can be used by DirectRequest() method.
@@ -171,8 +211,61 @@ func (rest *Rest) isTripped() bool {
 	return rest.trippedUntil.Load() > time.Now().UnixNano()
 }
 
-// Internal handler for buffered requests. It's used by Request() and RequestWithFiles() (for PATCH only) methods.
+// Returns current state of invalid request (401/403/429) tracker. Use it for monitoring - Discord will temporarily ban
+// your IP address if you reach 10,000 invalid requests within 10 minutes.
+func (rest *Rest) InvalidRequestStats() InvalidRequestStats {
+	return rest.invalidRequests.stats(time.Now())
+}
+
+// Interaction responses (callbacks, follow-ups and original response edits) get high priority by default.
+// High priority requests are never dropped when client reaches soft limit of invalid requests.
+// Only webhooks owned by bot's application are interaction webhooks - regular webhooks are not essential.
+func (rest *Rest) isEssentialRoute(route string) bool {
+	return isInteractionPath(route, rest.applicationID)
+}
+
+func (rest *Rest) recordInvalidRequest(res *http.Response) {
+	if res.StatusCode == http.StatusTooManyRequests && res.Header.Get("X-RateLimit-Scope") == "shared" {
+		return // Discord does not count those against invalid request limit.
+	}
+
+	now := time.Now()
+	if rest.invalidRequests.record(now) {
+		rest.trippedUntil.Store(now.Add(rest.invalidRequests.slotDuration).UnixNano())
+		rest.tracef("Reached hard limit of invalid requests! All requests are paused for at least %s.", rest.invalidRequests.slotDuration)
+	}
+}
+
+// Internal handler for buffered requests. It's used by Request() and RequestWithFiles() (when files cannot be streamed) methods.
 func (rest *Rest) DirectRequest(method, route string, body io.ReadSeeker, contentType string, auditLogReason string) ([]byte, error) {
+	return rest.do(method, route, seekableBody(body), -1, contentType, RequestOptions{AuditLogReason: auditLogReason})
+}
+
+// Returns current depth of rate limiter queues. Useful for monitoring whether background jobs pile up.
+func (rest *Rest) QueueStats() RateLimiterStats {
+	return rest.limiter.QueueStats()
+}
+
+func seekableBody(body io.ReadSeeker) bodyOpener {
+	if body == nil {
+		return nil
+	}
+
+	return func() (io.Reader, func() error, error) {
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, fmt.Errorf("failed to seek request body: %w", err)
+		}
+		return body, nil, nil
+	}
+}
+
+// Prepares fresh request body for each attempt. Optional bodyErr function reports error that happened
+// while producing streamed body - it's only called after failed attempt and such error is never retried.
+type bodyOpener func() (body io.Reader, bodyErr func() error, err error)
+
+// Core of every request - handles shedding, emergency breaks and retries.
+// Use contentLength = -1 when body length is unknown (or body is already seekable).
+func (rest *Rest) do(method, route string, open bodyOpener, contentLength int64, contentType string, opt RequestOptions) ([]byte, error) {
 	var (
 		responseBody []byte
 		lastErr      error
@@ -185,34 +278,79 @@ func (rest *Rest) DirectRequest(method, route string, body io.ReadSeeker, conten
 		}
 	}()
 
-	for i := uint8(0); i < rest.maxRetries; i++ {
-		if body != nil {
-			if _, err := body.Seek(0, io.SeekStart); err != nil {
-				return nil, fmt.Errorf("failed to seek request body: %w", err)
-			}
+	if opt.Priority == AUTO_REQUEST_PRIORITY {
+		opt.Priority = NORMAL_REQUEST_PRIORITY
+		if rest.isEssentialRoute(route) {
+			opt.Priority = HIGH_REQUEST_PRIORITY
 		}
+	}
+
+	if opt.Priority < HIGH_REQUEST_PRIORITY && rest.invalidRequests.shouldShed(time.Now()) {
+		return nil, errShedRequest
+	}
+
+	ctx := opt.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, requestPriorityKey{}, opt.Priority)
 
+	if contentType == "" {
+		return nil, errors.New("requests must have content type provided - for most Discord API requests, you probably want tempest.CONTENT_TYPE_JSON")
+	}
+
+	for i := uint8(0); i < rest.maxRetries; i++ {
 		if rest.isTripped() {
+			if rest.invalidRequests.stats(time.Now()).Tripped {
+				return nil, errInvalidRequests
+			}
 			return nil, errTooManyRetries
 		}
 
+		var (
+			body    io.Reader
+			bodyErr func() error
+		)
+
+		if open != nil {
+			var err error
+			body, bodyErr, err = open()
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// #nosec G704
-		req, err := http.NewRequest(method, DiscordAPIBaseURL()+route, body)
+		req, err := http.NewRequestWithContext(ctx, method, DiscordAPIBaseURL()+route, body)
 		if err != nil {
+			if bodyErr != nil {
+				bodyErr() //nolint:errcheck
+			}
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
 
-		if contentType == "" {
-			return nil, errors.New("requests must have content type provided - for most Discord API requests, you probably want tempest.CONTENT_TYPE_JSON")
+		if contentLength >= 0 && body != nil {
+			req.ContentLength = contentLength
 		}
+
 		req.Header.Set("Content-Type", contentType)
 
-		if auditLogReason != "" {
-			req.Header.Set("X-Audit-Log-Reason", url.PathEscape(auditLogReason))
+		if opt.AuditLogReason != "" {
+			req.Header.Set("X-Audit-Log-Reason", url.PathEscape(opt.AuditLogReason))
 		}
 
-		responseBody, err = rest.executeOnce(req)
+		if opt.Authorization != "" {
+			req.Header.Set("Authorization", opt.Authorization)
+		}
+
+		responseBody, err = rest.executeOnce(req, i)
 		if err != nil {
+			if bodyErr != nil {
+				if streamErr := bodyErr(); streamErr != nil {
+					return nil, streamErr // Problem with provided files - retrying won't help.
+				}
+			}
+
 			lastErr = err
 
 			if errors.Is(err, errGlobalRateLimit) || errors.Is(err, errRetryable) {
@@ -234,95 +372,33 @@ func (rest *Rest) DirectRequest(method, route string, body io.ReadSeeker, conten
 
 			return nil, err // Not a retryable error.
 		}
-		return responseBody, nil
-	}
-
-	return nil, fmt.Errorf("request failed after %d retries on %s %s: %w", rest.maxRetries, method, route, lastErr)
-}
-
-func (rest *Rest) RequestWithFiles(method string, route string, jsonPayload any, files []File) ([]byte, error) {
-	if len(files) == 0 {
-		return rest.Request(method, route, jsonPayload)
-	}
-
-	// To avoid issues with retrying requests that have streaming bodies (which can't be re-read),
-	// we pre-buffer the multipart request into memory. This ensures that the request body can be
-	// reliably sent multiple times in case of retries.
-	var requestBody bytes.Buffer
-	writer := multipart.NewWriter(&requestBody)
-	if err := writeMultipart(writer, jsonPayload, files); err != nil {
-		return nil, err
-	}
-
-	return rest.DirectRequest(method, route, bytes.NewReader(requestBody.Bytes()), writer.FormDataContentType(), "")
-}
 
-// Writes the JSON payload and files to a multipart writer.
-func writeMultipart(writer *multipart.Writer, jsonPayload any, files []File) error {
-	defer writer.Close() //nolint:errcheck
-
-	// Write JSON payload part.
-	jsonPart, err := writer.CreatePart(textproto.MIMEHeader{
-		"Content-Disposition": []string{`form-data; name="payload_json"`},
-		"Content-Type":        []string{CONTENT_TYPE_JSON},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create payload_json part: %w", err)
-	}
-	encoder := json.NewEncoder(jsonPart)
-	encoder.SetEscapeHTML(false)
-	if err := encoder.Encode(jsonPayload); err != nil {
-		return fmt.Errorf("failed to encode payload_json: %w", err)
-	}
-
-	// Write file parts.
-	for i, file := range files {
-		if seeker, ok := file.Reader.(io.ReadSeeker); ok {
-			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
-				return fmt.Errorf("failed to seek file [%s]: %w", file.Name, err)
-			}
-		}
-
-		filePart, err := writer.CreatePart(textproto.MIMEHeader{
-			"Content-Disposition": []string{fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, file.Name)},
-			"Content-Type":        []string{CONTENT_TYPE_OCTET_STREAM},
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create file part [%d]: %w", i, err)
-		}
-
-		lr := io.LimitReader(file.Reader, MAX_FILE_UPLOAD_SIZE+1)
-		n, err := io.Copy(filePart, lr)
-		if err != nil {
-			return fmt.Errorf("failed to stream file [%s]: %w", file.Name, err)
-		}
-		if n > MAX_FILE_UPLOAD_SIZE {
-			return fmt.Errorf("file [%s] exceeds maximum upload size of 10MB", file.Name)
+		if bodyErr != nil {
+			bodyErr() //nolint:errcheck
 		}
+		return responseBody, nil
 	}
 
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("failed to close multipart writer: %w", err)
-	}
-
-	return nil
+	return nil, fmt.Errorf("request failed after %d retries on %s %s: %w", rest.maxRetries, method, route, lastErr)
 }
 
 // executeOnce handles the lifecycle of a single request attempt.
-func (rest *Rest) executeOnce(req *http.Request) ([]byte, error) {
+func (rest *Rest) executeOnce(req *http.Request, attempt uint8) ([]byte, error) {
 	req.Header.Set("User-Agent", USER_AGENT)
-	req.Header.Set("Authorization", rest.token)
+	if req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", rest.token)
+	}
 
 	var res *http.Response
 	var err error
 	if rest.trace {
 		start := time.Now()
-		res, err = rest.HTTPClient.Do(req)
+		res, err = rest.roundTrip(req, attempt)
 		if err == nil {
 			rest.tracef("%s %s - Status: %s (took %v)", req.Method, req.URL.Path, res.Status, time.Since(start))
 		}
 	} else {
-		res, err = rest.HTTPClient.Do(req)
+		res, err = rest.roundTrip(req, attempt)
 	}
 
 	if err != nil {
@@ -339,6 +415,10 @@ func (rest *Rest) executeOnce(req *http.Request) ([]byte, error) {
 		return responseBody, nil
 	}
 
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusTooManyRequests {
+		rest.recordInvalidRequest(res)
+	}
+
 	if res.StatusCode == http.StatusTooManyRequests {
 		var rateErr rateLimitError
 		err = json.Unmarshal(responseBody, &rateErr)